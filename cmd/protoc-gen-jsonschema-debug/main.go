@@ -41,7 +41,7 @@ func main() {
 		pgs.DebugEnv(common.DebugEnv),
 		pgs.ProtocInput(reqFile),
 		pgs.ProtocOutput(resBytes),
-	).RegisterModule(module.New()).Render()
+	).RegisterModule(module.New("", nil)).Render() // The parameter of the request is protoc-gen-debug's, not the module's.
 
 	res := &pluginpb.CodeGeneratorResponse{}
 	if err := proto.Unmarshal(resBytes.Bytes(), res); err != nil {
@@ -4,14 +4,62 @@
 package main
 
 import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+
 	pgs "github.com/lyft/protoc-gen-star/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/pluginpb"
 
 	"github.com/cerbos/protoc-gen-jsonschema/internal/common"
 	"github.com/cerbos/protoc-gen-jsonschema/internal/module"
 )
 
+// The range of editions that the plugin supports, which protoc requires plugins supporting editions to declare.
+const (
+	minimumEdition = descriptorpb.Edition_EDITION_PROTO2
+	maximumEdition = descriptorpb.Edition_EDITION_2023
+)
+
 func main() {
+	// The module parses the parameter string of the request itself, so the request is read here before it's passed on.
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatalf("failed to read code generator request: %v", err)
+	}
+
+	request := &pluginpb.CodeGeneratorRequest{}
+	if err := proto.Unmarshal(input, request); err != nil {
+		log.Fatalf("failed to unmarshal code generator request: %v", err)
+	}
+
 	supportedFeatures := uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL | pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS)
-	pgs.Init(pgs.SupportedFeatures(&supportedFeatures), pgs.DebugEnv(common.DebugEnv)).RegisterModule(module.New()).Render()
+	output := &bytes.Buffer{}
+	pgs.Init(
+		pgs.SupportedFeatures(&supportedFeatures),
+		pgs.DebugEnv(common.DebugEnv),
+		pgs.ProtocInput(bytes.NewReader(input)),
+		pgs.ProtocOutput(output),
+	).RegisterModule(module.New(request.GetParameter(), request.GetCompilerVersion())).Render()
+
+	// protoc-gen-star can't set the edition range, so it's added to the response afterwards.
+	response := &pluginpb.CodeGeneratorResponse{}
+	if err := proto.Unmarshal(output.Bytes(), response); err != nil {
+		log.Fatalf("failed to unmarshal code generator response: %v", err)
+	}
+
+	response.MinimumEdition = proto.Int32(int32(minimumEdition))
+	response.MaximumEdition = proto.Int32(int32(maximumEdition))
+
+	data, err := proto.Marshal(response)
+	if err != nil {
+		log.Fatalf("failed to marshal code generator response: %v", err)
+	}
+
+	if _, err := os.Stdout.Write(data); err != nil {
+		log.Fatalf("failed to write code generator response: %v", err)
+	}
 }
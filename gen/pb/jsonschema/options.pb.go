@@ -0,0 +1,601 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: jsonschema/options.proto
+
+package jsonschema
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FileOptions customises the documents generated for the messages declared in a file, so that files can vary them
+// without separate invocations of protoc. Messages from other files that the documents define keep to them too.
+type FileOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id_base replaces the baseurl and id_template parameters for the documents generated for the file, whose $id is
+	// id_base followed by the path of the document. It can contain the same placeholders as id_template, such as
+	// {package_path}.
+	IdBase string `protobuf:"bytes,1,opt,name=id_base,json=idBase,proto3" json:"id_base,omitempty"`
+	// group replaces the group parameter for the file, and can be message or file. Files that set it are left out of the
+	// documents of their packages if the group parameter is package.
+	Group string `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	// draft replaces the draft parameter for the documents generated for the file. Files grouped by package have to agree
+	// on it with the other files in their packages.
+	Draft         string `protobuf:"bytes,3,opt,name=draft,proto3" json:"draft,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileOptions) Reset() {
+	*x = FileOptions{}
+	mi := &file_jsonschema_options_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileOptions) ProtoMessage() {}
+
+func (x *FileOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_jsonschema_options_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileOptions.ProtoReflect.Descriptor instead.
+func (*FileOptions) Descriptor() ([]byte, []int) {
+	return file_jsonschema_options_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FileOptions) GetIdBase() string {
+	if x != nil {
+		return x.IdBase
+	}
+	return ""
+}
+
+func (x *FileOptions) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *FileOptions) GetDraft() string {
+	if x != nil {
+		return x.Draft
+	}
+	return ""
+}
+
+// MessageOptions customises the schema generated for a message.
+type MessageOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id replaces the $id of the document generated for the message, which is otherwise built from the id_template
+	// parameter. It can contain the same placeholders as id_template, such as {filename}.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// filename replaces the path of the file that the document of the message is written to, which is otherwise built
+	// from the filename_template parameter. It can contain the same placeholders as filename_template, such as
+	// {package_path}.
+	Filename string `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	// skip leaves the message out of the messages that schemas are generated for, as if it were excluded by the exclude
+	// parameter. It is still defined in the schemas of the messages that reference it.
+	Skip bool `protobuf:"varint,3,opt,name=skip,proto3" json:"skip,omitempty"`
+	// examples are example values of the message, each given as JSON, which are listed under examples.
+	Examples []string `protobuf:"bytes,4,rep,name=examples,proto3" json:"examples,omitempty"`
+	// title is the title of the message's schema.
+	Title string `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	// description is the description of the message's schema, for when the comments of the message are aimed at its
+	// maintainers rather than its consumers.
+	Description string `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	// generate selects the message for generation when the only_annotated parameter is set, in which case schemas are
+	// only generated for messages that set it.
+	Generate      bool `protobuf:"varint,7,opt,name=generate,proto3" json:"generate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageOptions) Reset() {
+	*x = MessageOptions{}
+	mi := &file_jsonschema_options_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageOptions) ProtoMessage() {}
+
+func (x *MessageOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_jsonschema_options_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageOptions.ProtoReflect.Descriptor instead.
+func (*MessageOptions) Descriptor() ([]byte, []int) {
+	return file_jsonschema_options_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MessageOptions) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MessageOptions) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *MessageOptions) GetSkip() bool {
+	if x != nil {
+		return x.Skip
+	}
+	return false
+}
+
+func (x *MessageOptions) GetExamples() []string {
+	if x != nil {
+		return x.Examples
+	}
+	return nil
+}
+
+func (x *MessageOptions) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *MessageOptions) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *MessageOptions) GetGenerate() bool {
+	if x != nil {
+		return x.Generate
+	}
+	return false
+}
+
+// FieldOptions customises the schema generated for a field.
+type FieldOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// hide leaves the field out of the properties of the message, for fields that never appear in JSON payloads, such as
+	// those that are only used by servers. Since messages reject unknown properties, payloads that set the field are
+	// rejected.
+	Hide bool `protobuf:"varint,1,opt,name=hide,proto3" json:"hide,omitempty"`
+	// schema is a JSON Schema, given as JSON, that values of the field must also satisfy, for constraints that the
+	// generator can't express, such as bespoke patterns or contentSchema.
+	Schema string `protobuf:"bytes,2,opt,name=schema,proto3" json:"schema,omitempty"`
+	// replace_schema replaces the schema generated for the field with schema, rather than requiring values to satisfy
+	// both.
+	ReplaceSchema bool `protobuf:"varint,3,opt,name=replace_schema,json=replaceSchema,proto3" json:"replace_schema,omitempty"`
+	// format is the JSON Schema format of a string field, such as uuid, date or iri, for fields that don't have
+	// validation rules implying one.
+	Format string `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`
+	// examples are example values of the field, each given as JSON, which are listed under examples along with any
+	// examples in the field's validation rules.
+	Examples []string `protobuf:"bytes,5,rep,name=examples,proto3" json:"examples,omitempty"`
+	// title is the title of the field's schema.
+	Title string `protobuf:"bytes,6,opt,name=title,proto3" json:"title,omitempty"`
+	// description is the description of the field's schema, for when the comments of the field are aimed at its
+	// maintainers rather than its consumers.
+	Description string `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	// required requires the field's property, whatever the required and optional parameters are, for fields whose
+	// validation rules don't require them. Members of oneofs are still never required individually.
+	Required bool `protobuf:"varint,8,opt,name=required,proto3" json:"required,omitempty"`
+	// content_media_type is the media type of the content of a string or bytes field, such as application/json, for
+	// fields that carry structured payloads. It requires draft-07 or later.
+	ContentMediaType string `protobuf:"bytes,9,opt,name=content_media_type,json=contentMediaType,proto3" json:"content_media_type,omitempty"`
+	// content_schema is a JSON Schema, given as JSON, that the content of a string or bytes field satisfies once it is
+	// decoded according to content_media_type. It requires draft 2019-09 or later.
+	ContentSchema string `protobuf:"bytes,10,opt,name=content_schema,json=contentSchema,proto3" json:"content_schema,omitempty"`
+	// struct_schema is a JSON Schema, given as JSON, that replaces the schema of a google.protobuf.Struct field, which
+	// otherwise accepts any object, for structs that have a known shape. Values of the field must still be objects.
+	StructSchema string `protobuf:"bytes,11,opt,name=struct_schema,json=structSchema,proto3" json:"struct_schema,omitempty"`
+	// read_only marks the field as managed by servers, so that clients shouldn't set it, with the readOnly annotation. It
+	// requires draft-07 or later.
+	ReadOnly bool `protobuf:"varint,12,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	// write_only marks the field as never returned by servers, such as a password, with the writeOnly annotation. It
+	// requires draft-07 or later.
+	WriteOnly bool `protobuf:"varint,13,opt,name=write_only,json=writeOnly,proto3" json:"write_only,omitempty"`
+	// default is the default value of the field, given as JSON in the same form as protojson accepts for the field, which
+	// is recorded with the default annotation. proto3 fields can't otherwise declare defaults. It takes precedence over
+	// the default value of a proto2 field.
+	Default       string `protobuf:"bytes,14,opt,name=default,proto3" json:"default,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldOptions) Reset() {
+	*x = FieldOptions{}
+	mi := &file_jsonschema_options_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldOptions) ProtoMessage() {}
+
+func (x *FieldOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_jsonschema_options_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldOptions.ProtoReflect.Descriptor instead.
+func (*FieldOptions) Descriptor() ([]byte, []int) {
+	return file_jsonschema_options_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FieldOptions) GetHide() bool {
+	if x != nil {
+		return x.Hide
+	}
+	return false
+}
+
+func (x *FieldOptions) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *FieldOptions) GetReplaceSchema() bool {
+	if x != nil {
+		return x.ReplaceSchema
+	}
+	return false
+}
+
+func (x *FieldOptions) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *FieldOptions) GetExamples() []string {
+	if x != nil {
+		return x.Examples
+	}
+	return nil
+}
+
+func (x *FieldOptions) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *FieldOptions) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *FieldOptions) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *FieldOptions) GetContentMediaType() string {
+	if x != nil {
+		return x.ContentMediaType
+	}
+	return ""
+}
+
+func (x *FieldOptions) GetContentSchema() string {
+	if x != nil {
+		return x.ContentSchema
+	}
+	return ""
+}
+
+func (x *FieldOptions) GetStructSchema() string {
+	if x != nil {
+		return x.StructSchema
+	}
+	return ""
+}
+
+func (x *FieldOptions) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *FieldOptions) GetWriteOnly() bool {
+	if x != nil {
+		return x.WriteOnly
+	}
+	return false
+}
+
+func (x *FieldOptions) GetDefault() string {
+	if x != nil {
+		return x.Default
+	}
+	return ""
+}
+
+// EnumOptions customises the schema generated for an enum.
+type EnumOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// open replaces the open_enums parameter for the enum, so that its schema also accepts undeclared numbers if it is
+	// true, and only accepts declared values if it is false. Enums that protobuf treats as closed can't be open.
+	Open          *bool `protobuf:"varint,1,opt,name=open,proto3,oneof" json:"open,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnumOptions) Reset() {
+	*x = EnumOptions{}
+	mi := &file_jsonschema_options_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnumOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumOptions) ProtoMessage() {}
+
+func (x *EnumOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_jsonschema_options_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumOptions.ProtoReflect.Descriptor instead.
+func (*EnumOptions) Descriptor() ([]byte, []int) {
+	return file_jsonschema_options_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EnumOptions) GetOpen() bool {
+	if x != nil && x.Open != nil {
+		return *x.Open
+	}
+	return false
+}
+
+var file_jsonschema_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*FileOptions)(nil),
+		Field:         57300,
+		Name:          "jsonschema.file",
+		Tag:           "bytes,57300,opt,name=file",
+		Filename:      "jsonschema/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*MessageOptions)(nil),
+		Field:         57301,
+		Name:          "jsonschema.message",
+		Tag:           "bytes,57301,opt,name=message",
+		Filename:      "jsonschema/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*FieldOptions)(nil),
+		Field:         57302,
+		Name:          "jsonschema.field",
+		Tag:           "bytes,57302,opt,name=field",
+		Filename:      "jsonschema/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumOptions)(nil),
+		ExtensionType: (*EnumOptions)(nil),
+		Field:         57303,
+		Name:          "jsonschema.enum",
+		Tag:           "bytes,57303,opt,name=enum",
+		Filename:      "jsonschema/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FileOptions.
+var (
+	// file customises the documents generated for the messages declared in the file.
+	//
+	// optional jsonschema.FileOptions file = 57300;
+	E_File = &file_jsonschema_options_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// message customises the schema generated for the message.
+	//
+	// optional jsonschema.MessageOptions message = 57301;
+	E_Message = &file_jsonschema_options_proto_extTypes[1]
+)
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// field customises the schema generated for the field.
+	//
+	// optional jsonschema.FieldOptions field = 57302;
+	E_Field = &file_jsonschema_options_proto_extTypes[2]
+)
+
+// Extension fields to descriptorpb.EnumOptions.
+var (
+	// enum customises the schema generated for the enum.
+	//
+	// optional jsonschema.EnumOptions enum = 57303;
+	E_Enum = &file_jsonschema_options_proto_extTypes[3]
+)
+
+var File_jsonschema_options_proto protoreflect.FileDescriptor
+
+const file_jsonschema_options_proto_rawDesc = "" +
+	"\n" +
+	"\x18jsonschema/options.proto\x12\n" +
+	"jsonschema\x1a google/protobuf/descriptor.proto\"R\n" +
+	"\vFileOptions\x12\x17\n" +
+	"\aid_base\x18\x01 \x01(\tR\x06idBase\x12\x14\n" +
+	"\x05group\x18\x02 \x01(\tR\x05group\x12\x14\n" +
+	"\x05draft\x18\x03 \x01(\tR\x05draft\"\xc0\x01\n" +
+	"\x0eMessageOptions\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x12\n" +
+	"\x04skip\x18\x03 \x01(\bR\x04skip\x12\x1a\n" +
+	"\bexamples\x18\x04 \x03(\tR\bexamples\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bgenerate\x18\a \x01(\bR\bgenerate\"\xb9\x03\n" +
+	"\fFieldOptions\x12\x12\n" +
+	"\x04hide\x18\x01 \x01(\bR\x04hide\x12\x16\n" +
+	"\x06schema\x18\x02 \x01(\tR\x06schema\x12%\n" +
+	"\x0ereplace_schema\x18\x03 \x01(\bR\rreplaceSchema\x12\x16\n" +
+	"\x06format\x18\x04 \x01(\tR\x06format\x12\x1a\n" +
+	"\bexamples\x18\x05 \x03(\tR\bexamples\x12\x14\n" +
+	"\x05title\x18\x06 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x12\x1a\n" +
+	"\brequired\x18\b \x01(\bR\brequired\x12,\n" +
+	"\x12content_media_type\x18\t \x01(\tR\x10contentMediaType\x12%\n" +
+	"\x0econtent_schema\x18\n" +
+	" \x01(\tR\rcontentSchema\x12#\n" +
+	"\rstruct_schema\x18\v \x01(\tR\fstructSchema\x12\x1b\n" +
+	"\tread_only\x18\f \x01(\bR\breadOnly\x12\x1d\n" +
+	"\n" +
+	"write_only\x18\r \x01(\bR\twriteOnly\x12\x18\n" +
+	"\adefault\x18\x0e \x01(\tR\adefault\"/\n" +
+	"\vEnumOptions\x12\x17\n" +
+	"\x04open\x18\x01 \x01(\bH\x00R\x04open\x88\x01\x01B\a\n" +
+	"\x05_open:K\n" +
+	"\x04file\x12\x1c.google.protobuf.FileOptions\x18Կ\x03 \x01(\v2\x17.jsonschema.FileOptionsR\x04file:W\n" +
+	"\amessage\x12\x1f.google.protobuf.MessageOptions\x18տ\x03 \x01(\v2\x1a.jsonschema.MessageOptionsR\amessage:O\n" +
+	"\x05field\x12\x1d.google.protobuf.FieldOptions\x18ֿ\x03 \x01(\v2\x18.jsonschema.FieldOptionsR\x05field:K\n" +
+	"\x04enum\x12\x1c.google.protobuf.EnumOptions\x18\u05ff\x03 \x01(\v2\x17.jsonschema.EnumOptionsR\x04enumB;Z9github.com/cerbos/protoc-gen-jsonschema/gen/pb/jsonschemab\x06proto3"
+
+var (
+	file_jsonschema_options_proto_rawDescOnce sync.Once
+	file_jsonschema_options_proto_rawDescData []byte
+)
+
+func file_jsonschema_options_proto_rawDescGZIP() []byte {
+	file_jsonschema_options_proto_rawDescOnce.Do(func() {
+		file_jsonschema_options_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_jsonschema_options_proto_rawDesc), len(file_jsonschema_options_proto_rawDesc)))
+	})
+	return file_jsonschema_options_proto_rawDescData
+}
+
+var file_jsonschema_options_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_jsonschema_options_proto_goTypes = []any{
+	(*FileOptions)(nil),                 // 0: jsonschema.FileOptions
+	(*MessageOptions)(nil),              // 1: jsonschema.MessageOptions
+	(*FieldOptions)(nil),                // 2: jsonschema.FieldOptions
+	(*EnumOptions)(nil),                 // 3: jsonschema.EnumOptions
+	(*descriptorpb.FileOptions)(nil),    // 4: google.protobuf.FileOptions
+	(*descriptorpb.MessageOptions)(nil), // 5: google.protobuf.MessageOptions
+	(*descriptorpb.FieldOptions)(nil),   // 6: google.protobuf.FieldOptions
+	(*descriptorpb.EnumOptions)(nil),    // 7: google.protobuf.EnumOptions
+}
+var file_jsonschema_options_proto_depIdxs = []int32{
+	4, // 0: jsonschema.file:extendee -> google.protobuf.FileOptions
+	5, // 1: jsonschema.message:extendee -> google.protobuf.MessageOptions
+	6, // 2: jsonschema.field:extendee -> google.protobuf.FieldOptions
+	7, // 3: jsonschema.enum:extendee -> google.protobuf.EnumOptions
+	0, // 4: jsonschema.file:type_name -> jsonschema.FileOptions
+	1, // 5: jsonschema.message:type_name -> jsonschema.MessageOptions
+	2, // 6: jsonschema.field:type_name -> jsonschema.FieldOptions
+	3, // 7: jsonschema.enum:type_name -> jsonschema.EnumOptions
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	4, // [4:8] is the sub-list for extension type_name
+	0, // [0:4] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_jsonschema_options_proto_init() }
+func file_jsonschema_options_proto_init() {
+	if File_jsonschema_options_proto != nil {
+		return
+	}
+	file_jsonschema_options_proto_msgTypes[3].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_jsonschema_options_proto_rawDesc), len(file_jsonschema_options_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 4,
+			NumServices:   0,
+		},
+		GoTypes:           file_jsonschema_options_proto_goTypes,
+		DependencyIndexes: file_jsonschema_options_proto_depIdxs,
+		MessageInfos:      file_jsonschema_options_proto_msgTypes,
+		ExtensionInfos:    file_jsonschema_options_proto_extTypes,
+	}.Build()
+	File_jsonschema_options_proto = out.File
+	file_jsonschema_options_proto_goTypes = nil
+	file_jsonschema_options_proto_depIdxs = nil
+}
@@ -0,0 +1,395 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package jsonpatch applies JSON Patch documents, as defined by RFC 6902, to JSON documents.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Operation is a single operation of a JSON Patch document.
+type Operation struct {
+	Value any    `json:"value" yaml:"value"`
+	Op    string `json:"op" yaml:"op"`
+	Path  string `json:"path" yaml:"path"`
+	From  string `json:"from,omitempty" yaml:"from"`
+	// valueMissing and fromMissing record that a decoded operation had no value or from member, which can't otherwise be
+	// told apart from a null value or a from member pointing at the root.
+	valueMissing bool
+	fromMissing  bool
+}
+
+// plainOperation has the fields of Operation without its methods, so that it can be decoded in the usual way.
+type plainOperation Operation
+
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*plainOperation)(o)); err != nil {
+		return err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	_, hasValue := members["value"]
+	_, hasFrom := members["from"]
+	o.valueMissing, o.fromMissing = !hasValue, !hasFrom
+	return nil
+}
+
+func (o *Operation) UnmarshalYAML(node *yaml.Node) error {
+	if err := node.Decode((*plainOperation)(o)); err != nil {
+		return err
+	}
+
+	hasValue, hasFrom := false, false
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch node.Content[i].Value {
+		case "value":
+			hasValue = true
+		case "from":
+			hasFrom = true
+		}
+	}
+
+	o.valueMissing, o.fromMissing = !hasValue, !hasFrom
+	return nil
+}
+
+// Apply applies the operations to a JSON document in turn, keeping the order of the keys of its objects.
+func Apply(document []byte, patch []Operation) ([]byte, error) {
+	root, err := decode(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	for i, operation := range patch {
+		root, err = operation.apply(root)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, operation.Op, operation.Path, err)
+		}
+	}
+
+	return json.Marshal(root)
+}
+
+func (o Operation) apply(root any) (any, error) {
+	switch o.Op {
+	case "add":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+
+		return add(root, o.Path, value)
+
+	case "remove":
+		_, root, err := remove(root, o.Path)
+		return root, err
+
+	case "replace":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+
+		return replace(root, o.Path, value)
+
+	case "move":
+		if o.fromMissing {
+			return nil, errMissingFrom
+		}
+
+		if o.Path == o.From {
+			// Moving a value to where it already is leaves the document as it is, once the value is known to exist.
+			_, err := get(root, o.From)
+			return root, err
+		}
+
+		if strings.HasPrefix(o.Path, o.From+"/") {
+			return nil, fmt.Errorf("can't move %s into itself", o.From)
+		}
+
+		value, root, err := remove(root, o.From)
+		if err != nil {
+			return nil, err
+		}
+
+		return add(root, o.Path, value)
+
+	case "copy":
+		if o.fromMissing {
+			return nil, errMissingFrom
+		}
+
+		value, err := get(root, o.From)
+		if err != nil {
+			return nil, err
+		}
+
+		if value, err = normalize(value); err != nil {
+			return nil, err
+		}
+
+		return add(root, o.Path, value)
+
+	case "test":
+		if o.valueMissing {
+			return nil, errMissingValue
+		}
+
+		value, err := get(root, o.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		equal, err := equal(value, o.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if !equal {
+			return nil, fmt.Errorf("value at %s doesn't match", o.Path)
+		}
+
+		return root, nil
+
+	default:
+		return nil, fmt.Errorf("unknown operation %q", o.Op)
+	}
+}
+
+var (
+	errMissingValue = errors.New("missing value member")
+	errMissingFrom  = errors.New("missing from member")
+)
+
+// value returns the value of an add, replace or test operation, in the form produced by decode.
+func (o Operation) value() (any, error) {
+	if o.valueMissing {
+		return nil, errMissingValue
+	}
+
+	return normalize(o.Value)
+}
+
+// invalidEscape matches a tilde that isn't part of an escape sequence.
+var invalidEscape = regexp.MustCompile(`~([^01]|$)`)
+
+// parsePointer splits a JSON Pointer, as defined by RFC 6901, into its reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		if invalidEscape.MatchString(token) {
+			return nil, fmt.Errorf("invalid escape in JSON pointer %q", pointer)
+		}
+
+		// ~1 is unescaped first, so that ~01 becomes ~1 rather than /.
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// parent returns the container holding the value that a pointer refers to, along with the last token of the pointer.
+// It returns a nil container for the root.
+func parent(root any, pointer string) (any, string, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(tokens) == 0 {
+		return nil, "", nil
+	}
+
+	container := root
+	for _, token := range tokens[:len(tokens)-1] {
+		if container, err = child(container, token); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return container, tokens[len(tokens)-1], nil
+}
+
+func child(container any, token string) (any, error) {
+	switch c := container.(type) {
+	case *object:
+		value, ok := c.get(token)
+		if !ok {
+			return nil, fmt.Errorf("no member %q", token)
+		}
+
+		return value, nil
+
+	case *[]any:
+		index, err := arrayIndex(*c, token, false)
+		if err != nil {
+			return nil, err
+		}
+
+		return (*c)[index], nil
+
+	default:
+		return nil, fmt.Errorf("can't look up %q in a scalar", token)
+	}
+}
+
+// arrayIndex parses an array index, which may be one past the end of the array, or "-", when adding to it.
+func arrayIndex(array []any, token string, adding bool) (int, error) {
+	if adding && token == "-" {
+		return len(array), nil
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	limit := len(array)
+	if adding {
+		limit++
+	}
+
+	if index >= limit {
+		return 0, fmt.Errorf("array index %d out of range", index)
+	}
+
+	return index, nil
+}
+
+func get(root any, pointer string) (any, error) {
+	container, token, err := parent(root, pointer)
+	if err != nil || container == nil {
+		return root, err
+	}
+
+	return child(container, token)
+}
+
+func add(root any, pointer string, value any) (any, error) {
+	container, token, err := parent(root, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := container.(type) {
+	case nil:
+		return value, nil
+
+	case *object:
+		c.set(token, value)
+
+	case *[]any:
+		index, err := arrayIndex(*c, token, true)
+		if err != nil {
+			return nil, err
+		}
+
+		*c = append((*c)[:index], append([]any{value}, (*c)[index:]...)...)
+
+	default:
+		return nil, fmt.Errorf("can't add %q to a scalar", token)
+	}
+
+	return root, nil
+}
+
+// remove removes the value that a pointer refers to, returning it along with the new root.
+func remove(root any, pointer string) (any, any, error) {
+	container, token, err := parent(root, pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if container == nil {
+		return nil, nil, errors.New("can't remove the root of the document")
+	}
+
+	value, err := child(container, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch c := container.(type) {
+	case *object:
+		c.remove(token)
+
+	case *[]any:
+		index, err := arrayIndex(*c, token, false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		*c = append((*c)[:index], (*c)[index+1:]...)
+	}
+
+	return value, root, nil
+}
+
+// replace replaces the value that a pointer refers to, which must exist, keeping its place in its container.
+func replace(root any, pointer string, value any) (any, error) {
+	container, token, err := parent(root, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if container == nil {
+		return value, nil
+	}
+
+	if _, err := child(container, token); err != nil {
+		return nil, err
+	}
+
+	switch c := container.(type) {
+	case *object:
+		c.set(token, value)
+
+	case *[]any:
+		index, err := arrayIndex(*c, token, false)
+		if err != nil {
+			return nil, err
+		}
+
+		(*c)[index] = value
+	}
+
+	return root, nil
+}
+
+// equal compares two values by their JSON encodings, ignoring the order of the keys of objects.
+func equal(a, b any) (bool, error) {
+	var decoded [2]any
+	for i, value := range []any{a, b} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return false, err
+		}
+
+		if err := json.Unmarshal(data, &decoded[i]); err != nil {
+			return false, err
+		}
+	}
+
+	return reflect.DeepEqual(decoded[0], decoded[1]), nil
+}
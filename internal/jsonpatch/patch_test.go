@@ -0,0 +1,344 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonpatch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonpatch"
+)
+
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		name     string
+		document string
+		patch    string
+		want     string
+		wantErr  string
+	}{
+		// The examples of RFC 6902 appendix A, apart from A.13, which is about duplicate members in JSON.
+		{
+			name:     "A.1 adding an object member",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			want:     `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			name:     "A.2 adding an array element",
+			document: `{"foo": ["bar", "baz"]}`,
+			patch:    `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			want:     `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			name:     "A.3 removing an object member",
+			document: `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "remove", "path": "/baz"}]`,
+			want:     `{"foo": "bar"}`,
+		},
+		{
+			name:     "A.4 removing an array element",
+			document: `{"foo": ["bar", "qux", "baz"]}`,
+			patch:    `[{"op": "remove", "path": "/foo/1"}]`,
+			want:     `{"foo": ["bar", "baz"]}`,
+		},
+		{
+			name:     "A.5 replacing a value",
+			document: `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			want:     `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			name:     "A.6 moving a value",
+			document: `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			want:     `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			name:     "A.7 moving an array element",
+			document: `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch:    `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			want:     `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			name:     "A.8 testing a value: success",
+			document: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch:    `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			want:     `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			name:     "A.9 testing a value: error",
+			document: `{"baz": "qux"}`,
+			patch:    `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			wantErr:  "doesn't match",
+		},
+		{
+			name:     "A.10 adding a nested member object",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			want:     `{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			name:     "A.11 ignoring unrecognized elements",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			want:     `{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			name:     "A.12 adding to a nonexistent target",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			wantErr:  `no member "baz"`,
+		},
+		{
+			name:     "A.14 escape ordering",
+			document: `{"/": 9, "~1": 10}`,
+			patch:    `[{"op": "test", "path": "/~01", "value": 10}]`,
+			want:     `{"/": 9, "~1": 10}`,
+		},
+		{
+			name:     "A.15 comparing strings and numbers",
+			document: `{"/": 9, "~1": 10}`,
+			patch:    `[{"op": "test", "path": "/~01", "value": "10"}]`,
+			wantErr:  "doesn't match",
+		},
+		{
+			name:     "A.16 adding an array value",
+			document: `{"foo": ["bar"]}`,
+			patch:    `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			want:     `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+		{
+			name:     "add replaces an existing member",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/foo", "value": null}]`,
+			want:     `{"foo": null}`,
+		},
+		{
+			name:     "add at the end of an array",
+			document: `{"foo": [1, 2]}`,
+			patch:    `[{"op": "add", "path": "/foo/2", "value": 3}]`,
+			want:     `{"foo": [1, 2, 3]}`,
+		},
+		{
+			name:     "add past the end of an array",
+			document: `{"foo": [1, 2]}`,
+			patch:    `[{"op": "add", "path": "/foo/3", "value": 3}]`,
+			wantErr:  "out of range",
+		},
+		{
+			name:     "add with a leading zero index",
+			document: `{"foo": [1, 2]}`,
+			patch:    `[{"op": "add", "path": "/foo/01", "value": 3}]`,
+			wantErr:  "invalid array index",
+		},
+		{
+			name:     "add to a scalar",
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "add", "path": "/foo/bar", "value": 3}]`,
+			wantErr:  "scalar",
+		},
+		{
+			name:     "add to the root",
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "add", "path": "", "value": [1]}]`,
+			want:     `[1]`,
+		},
+		{
+			name:     "add without a value",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz"}]`,
+			wantErr:  "missing value",
+		},
+		{
+			name:     "remove a missing member",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "remove", "path": "/baz"}]`,
+			wantErr:  `no member "baz"`,
+		},
+		{
+			name:     "remove the end of an array",
+			document: `{"foo": [1, 2]}`,
+			patch:    `[{"op": "remove", "path": "/foo/-"}]`,
+			wantErr:  "invalid array index",
+		},
+		{
+			name:     "remove the root",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "remove", "path": ""}]`,
+			wantErr:  "root",
+		},
+		{
+			name:     "replace an array element",
+			document: `{"foo": [1, 2, 3]}`,
+			patch:    `[{"op": "replace", "path": "/foo/1", "value": "two"}]`,
+			want:     `{"foo": [1, "two", 3]}`,
+		},
+		{
+			name:     "replace a missing member",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "/baz", "value": 1}]`,
+			wantErr:  `no member "baz"`,
+		},
+		{
+			name:     "replace the root",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "", "value": {"baz": 1}}]`,
+			want:     `{"baz": 1}`,
+		},
+		{
+			name:     "replace without a value",
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "/foo"}]`,
+			wantErr:  "missing value",
+		},
+		{
+			name:     "move into itself",
+			document: `{"foo": {"bar": 1}}`,
+			patch:    `[{"op": "move", "from": "/foo", "path": "/foo/bar/baz"}]`,
+			wantErr:  "into itself",
+		},
+		{
+			name:     "move to a sibling sharing a prefix",
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "move", "from": "/foo", "path": "/foobar"}]`,
+			want:     `{"foobar": 1}`,
+		},
+		{
+			name:     "move to the same location",
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "move", "from": "/foo", "path": "/foo"}]`,
+			want:     `{"foo": 1}`,
+		},
+		{
+			name:     "move the root",
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "move", "from": "", "path": "/bar"}]`,
+			wantErr:  "into itself",
+		},
+		{
+			name:     "move without from",
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "move", "path": "/bar"}]`,
+			wantErr:  "missing from",
+		},
+		{
+			name:     "copy a value",
+			document: `{"foo": {"bar": [1]}}`,
+			patch:    `[{"op": "copy", "from": "/foo", "path": "/baz"}, {"op": "add", "path": "/baz/bar/-", "value": 2}]`,
+			want:     `{"foo": {"bar": [1]}, "baz": {"bar": [1, 2]}}`,
+		},
+		{
+			name:     "copy the root",
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "copy", "from": "", "path": "/bar"}]`,
+			want:     `{"foo": 1, "bar": {"foo": 1}}`,
+		},
+		{
+			name:     "copy without from",
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "copy", "path": "/bar"}]`,
+			wantErr:  "missing from",
+		},
+		{
+			name:     "test objects ignoring key order",
+			document: `{"foo": {"a": 1, "b": [true, null]}}`,
+			patch:    `[{"op": "test", "path": "/foo", "value": {"b": [true, null], "a": 1.0}}]`,
+			want:     `{"foo": {"a": 1, "b": [true, null]}}`,
+		},
+		{
+			name:     "test without a value",
+			document: `{"foo": null}`,
+			patch:    `[{"op": "test", "path": "/foo"}]`,
+			wantErr:  "missing value",
+		},
+		{
+			name:     "escaped tokens",
+			document: `{"a/b": {"m~n": 1}}`,
+			patch:    `[{"op": "replace", "path": "/a~1b/m~0n", "value": 2}]`,
+			want:     `{"a/b": {"m~n": 2}}`,
+		},
+		{
+			name:     "invalid escape",
+			document: `{"~2": 1}`,
+			patch:    `[{"op": "remove", "path": "/~2"}]`,
+			wantErr:  "invalid escape",
+		},
+		{
+			name:     "trailing tilde",
+			document: `{"~": 1}`,
+			patch:    `[{"op": "remove", "path": "/~"}]`,
+			wantErr:  "invalid escape",
+		},
+		{
+			name:     "pointer without a leading slash",
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "remove", "path": "foo"}]`,
+			wantErr:  "invalid JSON pointer",
+		},
+		{
+			name:     "unknown operation",
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "frobnicate", "path": "/foo"}]`,
+			wantErr:  "unknown operation",
+		},
+		{
+			name:     "errors name the failing operation",
+			document: `{"foo": 1}`,
+			patch:    `[{"op": "remove", "path": "/foo"}, {"op": "remove", "path": "/foo"}]`,
+			wantErr:  "operation 1 (remove /foo)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var patch []jsonpatch.Operation
+			require.NoError(t, json.Unmarshal([]byte(tc.patch), &patch))
+
+			have, err := jsonpatch.Apply([]byte(tc.document), patch)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.JSONEq(t, tc.want, string(have))
+		})
+	}
+}
+
+func TestApplyKeepsKeyOrder(t *testing.T) {
+	patch := []jsonpatch.Operation{
+		{Op: "add", Path: "/a", Value: 1},
+		{Op: "replace", Path: "/z", Value: map[string]any{"y": 2}},
+	}
+
+	have, err := jsonpatch.Apply([]byte(`{"z": 0, "m": {"c": 1, "b": 2}}`), patch)
+	require.NoError(t, err)
+	require.Equal(t, `{"z":{"y":2},"m":{"c":1,"b":2},"a":1}`, string(have))
+}
+
+func TestOperationUnmarshalYAML(t *testing.T) {
+	var patch []jsonpatch.Operation
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- op: add
+  path: /foo
+- op: add
+  path: /bar
+  value: null
+- op: copy
+  from: ""
+  path: /baz
+`), &patch))
+
+	_, err := jsonpatch.Apply([]byte(`{}`), patch[:1])
+	require.ErrorContains(t, err, "missing value")
+
+	have, err := jsonpatch.Apply([]byte(`{}`), patch[1:])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"bar": null, "baz": {"bar": null}}`, string(have))
+}
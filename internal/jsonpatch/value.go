@@ -0,0 +1,148 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var errTrailingData = errors.New("unexpected data after JSON value")
+
+// object is a JSON object that keeps its keys in order, so that patching a document doesn't reorder it.
+type object struct {
+	values map[string]any
+	keys   []string
+}
+
+func newObject() *object {
+	return &object{values: make(map[string]any)}
+}
+
+func (o *object) get(key string) (any, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+func (o *object) set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+
+	o.values[key] = value
+}
+
+func (o *object) remove(key string) {
+	o.keys = slices.DeleteFunc(o.keys, func(k string) bool { return k == key })
+	delete(o.values, key)
+}
+
+func (o *object) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		keyData, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		valueData, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(keyData)
+		buf.WriteByte(':')
+		buf.Write(valueData)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decode parses a JSON document into values in which objects are ordered and arrays are pointers to slices, so that
+// they can be modified in place.
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	value, err := decodeValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	if decoder.More() {
+		return nil, errTrailingData
+	}
+
+	return value, nil
+}
+
+func decodeValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		o := newObject()
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			key, ok := keyToken.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected object key %v", keyToken)
+			}
+
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			o.set(key, value)
+		}
+
+		_, err := decoder.Token()
+		return o, err
+
+	case json.Delim('['):
+		array := []any{}
+		for decoder.More() {
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			array = append(array, value)
+		}
+
+		_, err := decoder.Token()
+		return &array, err
+
+	default:
+		return token, nil
+	}
+}
+
+// normalize converts an arbitrary value, such as one read from a config file, into the form produced by decode.
+func normalize(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(data)
+}
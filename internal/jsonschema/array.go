@@ -14,3 +14,7 @@ type ArraySchema struct {
 func NewArraySchema() *ArraySchema {
 	return &ArraySchema{GenericSchema: GenericSchema{Type: "array"}}
 }
+
+func (s *ArraySchema) MarshalJSON() ([]byte, error) {
+	return marshal(s, s.Extensions)
+}
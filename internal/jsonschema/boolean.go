@@ -11,3 +11,7 @@ type BooleanSchema struct {
 func NewBooleanSchema() *BooleanSchema {
 	return &BooleanSchema{GenericSchema: GenericSchema{Type: "boolean"}}
 }
+
+func (s *BooleanSchema) MarshalJSON() ([]byte, error) {
+	return marshal(s, s.Extensions)
+}
@@ -0,0 +1,63 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonschema
+
+type Draft string
+
+const (
+	Draft04     Draft = "draft-04"
+	Draft06     Draft = "draft-06"
+	Draft07     Draft = "draft-07"
+	Draft201909 Draft = "2019-09"
+	Draft202012 Draft = "2020-12"
+)
+
+var Drafts = []Draft{Draft04, Draft06, Draft07, Draft201909, Draft202012}
+
+// URI identifies the meta-schema of the draft in the "$schema" keyword.
+func (d Draft) URI() string {
+	switch d {
+	case Draft201909, Draft202012:
+		return "https://json-schema.org/draft/" + string(d) + "/schema"
+	default:
+		return "http://json-schema.org/" + string(d) + "/schema#"
+	}
+}
+
+// BooleanExclusiveBounds reports whether "exclusiveMinimum" and "exclusiveMaximum" are flags that modify "minimum" and
+// "maximum", rather than bounds in their own right.
+func (d Draft) BooleanExclusiveBounds() bool {
+	return d == Draft04
+}
+
+// ContentKeywords reports whether the draft defines "contentEncoding" and "contentMediaType".
+func (d Draft) ContentKeywords() bool {
+	return d != Draft04 && d != Draft06
+}
+
+// Anchors reports whether the draft defines "$anchor", which names a subschema with a plain-name fragment.
+func (d Draft) Anchors() bool {
+	return d == Draft201909 || d == Draft202012
+}
+
+// ContentSchemaKeyword reports whether the draft defines "contentSchema", which describes decoded content.
+func (d Draft) ContentSchemaKeyword() bool {
+	return d == Draft201909 || d == Draft202012
+}
+
+// AccessKeywords reports whether the draft defines "readOnly" and "writeOnly".
+func (d Draft) AccessKeywords() bool {
+	return d != Draft04 && d != Draft06
+}
+
+// DefinitionsKeyword returns the keyword that holds reusable schemas, which was renamed from "definitions" to "$defs"
+// in draft 2019-09.
+func (d Draft) DefinitionsKeyword() string {
+	switch d {
+	case Draft201909, Draft202012:
+		return "$defs"
+	default:
+		return "definitions"
+	}
+}
@@ -5,23 +5,44 @@ package jsonschema
 
 //nolint:govet
 type GenericSchema struct {
-	ID          string             `json:"$id,omitempty"`
-	Version     string             `json:"$schema,omitempty"`
-	Ref         string             `json:"$ref,omitempty"`
-	Definitions map[string]Schema  `json:"definitions,omitempty"`
-	Title       string             `json:"title,omitempty"`
-	Description string             `json:"description,omitempty"`
-	Type        string             `json:"type,omitempty"`
-	AllOf       []NonTrivialSchema `json:"allOf,omitempty"`
-	AnyOf       []NonTrivialSchema `json:"anyOf,omitempty"`
-	OneOf       []NonTrivialSchema `json:"oneOf,omitempty"`
-	Not         Schema             `json:"not,omitempty"`
+	ID            string             `json:"$id,omitempty"`
+	LegacyID      string             `json:"id,omitempty"`
+	Version       string             `json:"$schema,omitempty"`
+	Comment       string             `json:"$comment,omitempty"`
+	Anchor        string             `json:"$anchor,omitempty"`
+	DynamicAnchor string             `json:"$dynamicAnchor,omitempty"`
+	Ref           string             `json:"$ref,omitempty"`
+	DynamicRef    string             `json:"$dynamicRef,omitempty"`
+	Defs          map[string]Schema  `json:"$defs,omitempty"`
+	Definitions   map[string]Schema  `json:"definitions,omitempty"`
+	Title         string             `json:"title,omitempty"`
+	Description   string             `json:"description,omitempty"`
+	Default       any                `json:"default,omitempty"`
+	Examples      []any              `json:"examples,omitempty"`
+	ReadOnly      bool               `json:"readOnly,omitempty"`
+	WriteOnly     bool               `json:"writeOnly,omitempty"`
+	Type          string             `json:"type,omitempty"`
+	AllOf         []NonTrivialSchema `json:"allOf,omitempty"`
+	AnyOf         []NonTrivialSchema `json:"anyOf,omitempty"`
+	OneOf         []NonTrivialSchema `json:"oneOf,omitempty"`
+	Not           Schema             `json:"not,omitempty"`
+	Extensions    map[string]any     `json:"-"`
+}
+
+func NewNullSchema() *GenericSchema {
+	return &GenericSchema{Type: "null"}
 }
 
 func Ref(ref string) *GenericSchema {
 	return &GenericSchema{Ref: ref}
 }
 
+// DynamicRef returns a reference that resolves to the outermost schema in the dynamic scope with a matching
+// "$dynamicAnchor", if the schema that it initially resolves to has one.
+func DynamicRef(ref string) *GenericSchema {
+	return &GenericSchema{DynamicRef: ref}
+}
+
 func AllOf(schemas ...NonTrivialSchema) NonTrivialSchema {
 	if len(schemas) == 1 {
 		return schemas[0]
@@ -50,13 +71,185 @@ func Not(schema Schema) *GenericSchema {
 	return &GenericSchema{Not: schema}
 }
 
-func (s *GenericSchema) Define(definitions map[string]Schema) {
-	s.Definitions = definitions
+// Nullable extends a schema to also accept null.
+func Nullable(schema Schema) Schema {
+	switch s := schema.(type) {
+	case NonTrivialSchema:
+		return AnyOf(s, NewNullSchema())
+	case TrivialSchema:
+		if s {
+			return s
+		}
+
+		return NewNullSchema()
+	default:
+		return schema
+	}
+}
+
+// WithExamples annotates a schema with example values.
+func WithExamples(schema Schema, examples ...any) Schema {
+	if len(examples) == 0 {
+		return schema
+	}
+
+	return annotate(schema, func(s NonTrivialSchema) { s.AddExamples(examples...) })
+}
+
+// WithTitle annotates a schema with a title.
+func WithTitle(schema Schema, title string) Schema {
+	if title == "" {
+		return schema
+	}
+
+	return annotate(schema, func(s NonTrivialSchema) { s.SetTitle(title) })
+}
+
+// WithDescription annotates a schema with a description.
+func WithDescription(schema Schema, description string) Schema {
+	if description == "" {
+		return schema
+	}
+
+	return annotate(schema, func(s NonTrivialSchema) { s.SetDescription(description) })
+}
+
+// WithReadOnly annotates a schema as describing a value that is managed by its owner, and shouldn't be modified.
+func WithReadOnly(schema Schema) Schema {
+	return annotate(schema, func(s NonTrivialSchema) { s.SetReadOnly() })
+}
+
+// WithWriteOnly annotates a schema as describing a value that is never returned by its owner.
+func WithWriteOnly(schema Schema) Schema {
+	return annotate(schema, func(s NonTrivialSchema) { s.SetWriteOnly() })
+}
+
+// WithDefault annotates a schema with a default value.
+func WithDefault(schema Schema, value any) Schema {
+	return annotate(schema, func(s NonTrivialSchema) { s.SetDefault(value) })
+}
+
+// WithAnchor names a schema with a plain-name fragment, so that it can be referenced as "#" followed by the name
+// wherever it is defined in the document.
+func WithAnchor(schema Schema, anchor string) Schema {
+	switch s := schema.(type) {
+	case NonTrivialSchema:
+		s.SetAnchor(anchor)
+		return s
+	case TrivialSchema:
+		if s {
+			return &GenericSchema{Anchor: anchor}
+		}
+
+		return &GenericSchema{Anchor: anchor, Not: True}
+	default:
+		return schema
+	}
+}
+
+// WithDynamicAnchor names a schema with a fragment that "$dynamicRef" can resolve to, including from schemas that
+// extend the one it is defined in.
+func WithDynamicAnchor(schema Schema, anchor string) Schema {
+	switch s := schema.(type) {
+	case NonTrivialSchema:
+		s.SetDynamicAnchor(anchor)
+		return s
+	case TrivialSchema:
+		if s {
+			return &GenericSchema{DynamicAnchor: anchor}
+		}
+
+		return &GenericSchema{DynamicAnchor: anchor, Not: True}
+	default:
+		return schema
+	}
+}
+
+// annotate applies annotations to a schema. References are wrapped, because draft-07 ignores keywords alongside "$ref".
+func annotate(schema Schema, apply func(NonTrivialSchema)) Schema {
+	constrained, ok := schema.(NonTrivialSchema)
+	if !ok {
+		return schema
+	}
+
+	if generic, ok := constrained.(*GenericSchema); ok && generic.Ref != "" {
+		constrained = &GenericSchema{AllOf: []NonTrivialSchema{generic}}
+	}
+
+	apply(constrained)
+	return constrained
+}
+
+// SetExtension adds a keyword that is not modelled by the schema types, such as vendor extensions prefixed with "x-".
+func (s *GenericSchema) SetExtension(keyword string, value any) {
+	if s.Extensions == nil {
+		s.Extensions = make(map[string]any)
+	}
+
+	s.Extensions[keyword] = value
+}
+
+func (s *GenericSchema) AddExamples(examples ...any) {
+	s.Examples = append(s.Examples, examples...)
+}
+
+func (s *GenericSchema) SetDefault(value any) {
+	s.Default = value
+}
+
+func (s *GenericSchema) SetReadOnly() {
+	s.ReadOnly = true
+}
+
+func (s *GenericSchema) SetWriteOnly() {
+	s.WriteOnly = true
+}
+
+func (s *GenericSchema) SetTitle(title string) {
+	s.Title = title
+}
+
+func (s *GenericSchema) SetDescription(description string) {
+	s.Description = description
+}
+
+func (s *GenericSchema) Define(definitions map[string]Schema, draft Draft) {
+	if draft.DefinitionsKeyword() == "$defs" {
+		s.Defs = definitions
+	} else {
+		s.Definitions = definitions
+	}
+}
+
+func (s *GenericSchema) TopLevel(id string, draft Draft) {
+	if draft == Draft04 {
+		s.LegacyID = id
+	} else {
+		s.ID = id
+	}
+
+	s.Version = draft.URI()
+}
+
+func (s *GenericSchema) SetAnchor(anchor string) {
+	s.Anchor = anchor
+}
+
+func (s *GenericSchema) SetDynamicAnchor(anchor string) {
+	s.DynamicAnchor = anchor
+}
+
+func (s *GenericSchema) SetComment(comment string) {
+	s.Comment = comment
+}
+
+// SetMetaSchema replaces the meta-schema of a top-level schema, which is the draft's by default.
+func (s *GenericSchema) SetMetaSchema(uri string) {
+	s.Version = uri
 }
 
-func (s *GenericSchema) TopLevel(id string) {
-	s.ID = id
-	s.Version = "http://json-schema.org/draft-07/schema#"
+func (s *GenericSchema) MarshalJSON() ([]byte, error) {
+	return marshal(s, s.Extensions)
 }
 
 func (*GenericSchema) implementsSchema() {}
@@ -0,0 +1,131 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// marshal encodes a schema struct as a JSON object, flattening embedded structs like encoding/json does
+// and appending any extension keywords after the standard ones. An extension keyword with the same name as a standard
+// one replaces it, so that no key is written twice.
+func marshal(schema any, extensions map[string]any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+
+	first := true
+	writeKey := func(key string) error {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		data, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(data)
+		buf.WriteByte(':')
+		return nil
+	}
+
+	var writeFields func(value reflect.Value) error
+	writeFields = func(value reflect.Value) error {
+		for i := range value.NumField() {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			if field.Anonymous {
+				if err := writeFields(value.Field(i)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+
+			if name == "" {
+				name = field.Name
+			}
+
+			if _, ok := extensions[name]; ok {
+				continue
+			}
+
+			if options == "omitempty" && isEmptyValue(value.Field(i)) {
+				continue
+			}
+
+			if options == "omitzero" && value.Field(i).IsZero() {
+				continue
+			}
+
+			data, err := json.Marshal(value.Field(i).Interface())
+			if err != nil {
+				return err
+			}
+
+			if err := writeKey(name); err != nil {
+				return err
+			}
+			buf.Write(data)
+		}
+
+		return nil
+	}
+
+	if err := writeFields(reflect.ValueOf(schema).Elem()); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(extensions))
+	for key := range extensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		data, err := json.Marshal(extensions[key])
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeKey(key); err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() { //nolint:exhaustive
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
@@ -3,10 +3,20 @@
 
 package jsonschema
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"math/big"
+)
 
 type Number json.RawMessage
 
+// Less reports whether the number is less than another. Numbers that can't be parsed are never less than anything.
+func (n Number) Less(other Number) bool {
+	a, okA := new(big.Rat).SetString(string(n))
+	b, okB := new(big.Rat).SetString(string(other))
+	return okA && okB && a.Cmp(b) < 0
+}
+
 func (n Number) IsNegative() bool {
 	return len(n) > 1 && n[0] == '-'
 }
@@ -44,3 +54,29 @@ func NewIntegerSchema() *NumberSchema {
 func NewNumberSchema() *NumberSchema {
 	return &NumberSchema{GenericSchema: GenericSchema{Type: "number"}}
 }
+
+// SetExclusiveMaximum sets an exclusive upper bound in the form used by the draft.
+func (s *NumberSchema) SetExclusiveMaximum(value Number, draft Draft) {
+	if draft.BooleanExclusiveBounds() {
+		s.Maximum = value
+		s.ExclusiveMaximum = Number("true")
+		return
+	}
+
+	s.ExclusiveMaximum = value
+}
+
+// SetExclusiveMinimum sets an exclusive lower bound in the form used by the draft.
+func (s *NumberSchema) SetExclusiveMinimum(value Number, draft Draft) {
+	if draft.BooleanExclusiveBounds() {
+		s.Minimum = value
+		s.ExclusiveMinimum = Number("true")
+		return
+	}
+
+	s.ExclusiveMinimum = value
+}
+
+func (s *NumberSchema) MarshalJSON() ([]byte, error) {
+	return marshal(s, s.Extensions)
+}
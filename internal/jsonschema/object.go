@@ -6,17 +6,28 @@ package jsonschema
 //nolint:govet
 type ObjectSchema struct {
 	GenericSchema
-	MaxProperties        *uint64           `json:"maxProperties,omitempty"`
-	MinProperties        *uint64           `json:"minProperties,omitempty"`
-	Required             []string          `json:"required,omitempty"`
-	AdditionalProperties Schema            `json:"additionalProperties,omitempty"`
-	Properties           map[string]Schema `json:"properties,omitempty"`
-	PropertyNames        Schema            `json:"propertyNames,omitempty"`
+	MaxProperties        *uint64     `json:"maxProperties,omitempty"`
+	MinProperties        *uint64     `json:"minProperties,omitempty"`
+	Required             []string    `json:"required,omitempty"`
+	AdditionalProperties Schema      `json:"additionalProperties,omitempty"`
+	Properties           *Properties `json:"properties,omitempty"`
+	PropertyNames        Schema      `json:"propertyNames,omitempty"`
 }
 
 func NewObjectSchema() *ObjectSchema {
+	return &ObjectSchema{GenericSchema: GenericSchema{Type: "object"}}
+}
+
+// NewClosedObjectSchema returns an object schema that rejects any properties that aren't declared, which is all of them
+// until some are added.
+func NewClosedObjectSchema() *ObjectSchema {
 	return &ObjectSchema{
-		GenericSchema: GenericSchema{Type: "object"},
-		Properties:    make(map[string]Schema),
+		GenericSchema:        GenericSchema{Type: "object"},
+		AdditionalProperties: False,
+		Properties:           NewProperties(),
 	}
 }
+
+func (s *ObjectSchema) MarshalJSON() ([]byte, error) {
+	return marshal(s, s.Extensions)
+}
@@ -0,0 +1,70 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+)
+
+// Properties maps the names of an object's properties to their schemas, and encodes them in the order that they were
+// added, so that generated schemas follow the order in which fields are declared.
+type Properties struct {
+	names   []string
+	schemas map[string]Schema
+}
+
+func NewProperties() *Properties {
+	return &Properties{schemas: make(map[string]Schema)}
+}
+
+// Set adds a property after the existing ones, or replaces the schema of an existing property in place.
+func (p *Properties) Set(name string, schema Schema) {
+	if _, ok := p.schemas[name]; !ok {
+		p.names = append(p.names, name)
+	}
+
+	p.schemas[name] = schema
+}
+
+// SetFirst adds a property before the existing ones, or moves an existing property to the front.
+func (p *Properties) SetFirst(name string, schema Schema) {
+	p.names = slices.DeleteFunc(p.names, func(existing string) bool { return existing == name })
+	p.names = append([]string{name}, p.names...)
+	p.schemas[name] = schema
+}
+
+// Names returns the names of the properties in order.
+func (p *Properties) Names() []string {
+	return slices.Clone(p.names)
+}
+
+func (p *Properties) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+
+	for i, name := range p.names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(p.schemas[name])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
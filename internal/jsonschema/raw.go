@@ -0,0 +1,37 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var errInvalidRawSchema = errors.New("a schema must be a JSON object or boolean")
+
+// RawSchema is a schema given as JSON, for constraints that the schema types don't model. Keywords can't be added to
+// it, so it should only be used as a subschema of another schema, such as in "allOf".
+type RawSchema struct {
+	GenericSchema
+	data json.RawMessage
+}
+
+// Raw parses a schema given as JSON.
+func Raw(data []byte) (*RawSchema, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	switch value.(type) {
+	case map[string]any, bool:
+		return &RawSchema{data: data}, nil
+	default:
+		return nil, errInvalidRawSchema
+	}
+}
+
+func (s *RawSchema) MarshalJSON() ([]byte, error) {
+	return s.data, nil
+}
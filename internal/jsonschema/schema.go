@@ -14,8 +14,19 @@ type Schema interface {
 
 type NonTrivialSchema interface {
 	Schema
-	Define(definitions map[string]Schema)
-	TopLevel(id string)
+	AddExamples(examples ...any)
+	SetDefault(value any)
+	SetTitle(title string)
+	SetDescription(description string)
+	SetReadOnly()
+	SetWriteOnly()
+	SetAnchor(anchor string)
+	SetDynamicAnchor(anchor string)
+	Define(definitions map[string]Schema, draft Draft)
+	TopLevel(id string, draft Draft)
+	SetMetaSchema(uri string)
+	SetComment(comment string)
+	SetExtension(keyword string, value any)
 }
 
 type TrivialSchema bool
@@ -13,6 +13,7 @@ const (
 	StringFormatIPv6         StringFormat = "ipv6"
 	StringFormatURI          StringFormat = "uri"
 	StringFormatURIReference StringFormat = "uri-reference"
+	StringFormatUUID         StringFormat = "uuid"
 )
 
 //nolint:govet
@@ -24,8 +25,17 @@ type StringSchema struct {
 	MinLength *uint64      `json:"minLength,omitempty"`
 	Pattern   string       `json:"pattern,omitempty"`
 	Format    StringFormat `json:"format,omitempty"`
+	// ContentEncoding and ContentMediaType are only defined from draft-07 onwards.
+	ContentEncoding  string `json:"contentEncoding,omitempty"`
+	ContentMediaType string `json:"contentMediaType,omitempty"`
+	// ContentSchema is only defined from draft 2019-09 onwards.
+	ContentSchema Schema `json:"contentSchema,omitempty"`
 }
 
 func NewStringSchema() *StringSchema {
 	return &StringSchema{GenericSchema: GenericSchema{Type: "string"}}
 }
+
+func (s *StringSchema) MarshalJSON() ([]byte, error) {
+	return marshal(s, s.Extensions)
+}
@@ -0,0 +1,33 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonschema
+
+const (
+	vocabularyURIPrefix       = "https://json-schema.org/draft/2020-12/vocab/"
+	formatAssertionMetaSchema = "https://json-schema.org/draft/2020-12/meta/format-assertion"
+)
+
+// vocabularies are the vocabularies of the draft 2020-12 meta-schema, which the format-assertion meta-schema also
+// requires.
+var vocabularies = []string{"core", "applicator", "unevaluated", "validation", "meta-data", "content"}
+
+// NewFormatAssertionMetaSchema returns a meta-schema that extends draft 2020-12 with the format-assertion vocabulary.
+// Validators that support the vocabulary treat "format" as an assertion in schemas that use the meta-schema, and
+// validators that don't support it refuse to process them, rather than silently ignoring their formats.
+func NewFormatAssertionMetaSchema(id string) *GenericSchema {
+	vocabulary := make(map[string]bool)
+	for _, name := range vocabularies {
+		vocabulary[vocabularyURIPrefix+name] = true
+	}
+	vocabulary[vocabularyURIPrefix+"format-assertion"] = true
+
+	schema := &GenericSchema{
+		DynamicAnchor: "meta",
+		Title:         "Draft 2020-12 with format assertions",
+		AllOf:         []NonTrivialSchema{Ref(Draft202012.URI()), Ref(formatAssertionMetaSchema)},
+	}
+	schema.TopLevel(id, Draft202012)
+	schema.SetExtension("$vocabulary", vocabulary)
+	return schema
+}
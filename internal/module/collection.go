@@ -4,22 +4,21 @@
 package module
 
 import (
+	"strconv"
+
 	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	pgs "github.com/lyft/protoc-gen-star/v2"
 
 	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
 )
 
-func (m *Module) schemaForMap(value pgs.FieldTypeElem, rules *validate.MapRules) jsonschema.Schema {
-	m.Debug("schemaForMap")
+func (m *Module) schemaForMap(key, value pgs.FieldTypeElem, rules *validate.MapRules) jsonschema.Schema {
+	m.debug("schemaForMap")
 	schema := jsonschema.NewObjectSchema()
 	schema.AdditionalProperties = m.schemaForElement(value, rules.GetValues())
+	schema.PropertyNames = m.schemaForMapKey(key, rules.GetKeys())
 
 	if rules != nil {
-		if rules.GetKeys().GetString() != nil {
-			schema.PropertyNames = m.schemaForString(rules.GetKeys().GetString())
-		}
-
 		if rules.MaxPairs != nil {
 			schema.MaxProperties = jsonschema.Size(rules.GetMaxPairs())
 		}
@@ -32,8 +31,67 @@ func (m *Module) schemaForMap(value pgs.FieldTypeElem, rules *validate.MapRules)
 	return schema
 }
 
+func (m *Module) schemaForMapKey(key pgs.FieldTypeElem, rules *validate.FieldRules) jsonschema.Schema {
+	m.debug("schemaForMapKey")
+	if rules.GetIgnore() == validate.Ignore_IGNORE_ALWAYS {
+		return nil
+	}
+
+	// protojson encodes all map keys as strings, so bool and integer keys have to be matched by pattern.
+	switch key.ProtoType() {
+	case pgs.StringT:
+		if rules.GetString() != nil {
+			return m.schemaForString(rules.GetString())
+		}
+
+		return nil
+
+	case pgs.BoolT:
+		schema := jsonschema.NewStringSchema()
+		schema.Pattern = `^(?:true|false)$`
+		if rules.GetBool().HasConst() {
+			schema.Const = jsonschema.String(strconv.FormatBool(rules.GetBool().GetConst()))
+		}
+
+		return schema
+
+	default:
+		return m.schemaForIntegerMapKey(key.ProtoType(), rules)
+	}
+}
+
+// schemaForIntegerMapKey returns the schema for the decimal strings that protojson uses to encode integer map keys.
+func (m *Module) schemaForIntegerMapKey(numeric pgs.ProtoType, rules *validate.FieldRules) jsonschema.Schema {
+	m.debug("schemaForIntegerMapKey")
+	schema := jsonschema.NewStringSchema()
+	schema.Pattern = integerRangePattern(m.integerTypeRange(numeric))
+
+	r := m.numericRules(numeric, rules)
+	if r == nil {
+		return schema
+	}
+
+	if r.Const != nil {
+		schema.Const = jsonschema.String(string(r.Const))
+	}
+
+	m.applyIntegerStringBounds(numeric, schema, r)
+
+	if len(r.In) > 0 {
+		schema.Enum = numbersToStrings(r.In)
+	}
+
+	if len(r.NotIn) > 0 {
+		notIn := jsonschema.NewStringSchema()
+		notIn.Enum = numbersToStrings(r.NotIn)
+		return jsonschema.AllOf(schema, jsonschema.Not(notIn))
+	}
+
+	return schema
+}
+
 func (m *Module) schemaForRepeated(item pgs.FieldTypeElem, rules *validate.RepeatedRules) jsonschema.Schema {
-	m.Debug("schemaForRepeated")
+	m.debug("schemaForRepeated")
 	schema := jsonschema.NewArraySchema()
 	schema.Items = m.schemaForElement(item, rules.GetItems())
 
@@ -55,14 +113,20 @@ func (m *Module) schemaForRepeated(item pgs.FieldTypeElem, rules *validate.Repea
 }
 
 func (m *Module) schemaForElement(element pgs.FieldTypeElem, rules *validate.FieldRules) jsonschema.Schema {
-	m.Debug("schemaForElement")
-	if element.IsEmbed() {
-		return m.schemaForEmbed(element.Embed(), rules)
+	m.debug("schemaForElement")
+	if rules.GetIgnore() == validate.Ignore_IGNORE_ALWAYS {
+		rules = nil
 	}
 
-	if element.IsEnum() {
-		return m.schemaForEnum(element.Enum(), rules.GetEnum())
+	var schema jsonschema.Schema
+	switch {
+	case element.IsEmbed():
+		schema = m.schemaForEmbed(element.Embed(), rules)
+	case element.IsEnum():
+		schema = m.schemaForEnum(element.Enum(), rules.GetEnum())
+	default:
+		schema = m.schemaForScalar(element.ProtoType(), rules)
 	}
 
-	return m.schemaForScalar(element.ProtoType(), rules)
+	return m.applyExamples(element, rules, m.applyIgnore(m.schemaForZeroValue(element), rules, schema))
 }
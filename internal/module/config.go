@@ -0,0 +1,119 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"os"
+	"strings"
+
+	pgs "github.com/lyft/protoc-gen-star/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonpatch"
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+// Keys of the config file that don't hold parameters.
+const (
+	// configMessages holds overrides for individual messages.
+	configMessages = "messages"
+	// configPatches holds a JSON patch that is applied to every document.
+	configPatches = "patches"
+)
+
+// messageOverride adjusts the schema generated for a message, which is given by its fully-qualified name in the config
+// file.
+type messageOverride struct {
+	// AdditionalProperties replaces the default of rejecting unknown properties.
+	AdditionalProperties *bool `yaml:"additionalProperties"`
+	// Keywords are added to the schema, replacing any that the generator produces itself.
+	Keywords map[string]any `yaml:"keywords"`
+	// Patches is a JSON patch that is applied to the document generated for the message, after any global patches.
+	Patches []jsonpatch.Operation `yaml:"patches"`
+	// ID replaces the $id of the document generated for the message.
+	ID string `yaml:"id"`
+	// Title sets the title of the schema.
+	Title string `yaml:"title"`
+	// Required replaces the value of the required parameter for the fields of the message.
+	Required requiredMode `yaml:"required"`
+}
+
+// loadConfig reads parameters from a YAML file that maps their names to their values, for builds whose parameters
+// don't fit comfortably in the option string. Parameters given in the option string take precedence. The file can
+// also override the schemas of individual messages.
+func (m *Module) loadConfig(path string) {
+	m.debug("loadConfig", "path", path)
+	data, err := os.ReadFile(path) //nolint:gosec // The path is chosen by whoever runs the plugin.
+	m.CheckErr(err, "failed to read config file")
+
+	var values map[string]yaml.Node
+	m.CheckErr(yaml.Unmarshal(data, &values), "failed to parse config file")
+
+	for name, node := range values {
+		switch name {
+		case configMessages:
+			m.CheckErr(node.Decode(&m.overrides), "invalid value for messages in config file")
+			continue
+
+		case configPatches:
+			m.CheckErr(node.Decode(&m.patches), "invalid value for patches in config file")
+			continue
+		}
+
+		if _, ok := m.params[name]; ok {
+			continue
+		}
+
+		m.CheckErr(m.params.add(name, m.configValue(name, &node)), "invalid config file")
+	}
+}
+
+// configValue converts a value from the config file to the form it takes in the option string, in which lists of
+// patterns are separated by semicolons.
+func (m *Module) configValue(name string, node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+
+	case yaml.SequenceNode:
+		var values []string
+		m.CheckErr(node.Decode(&values), "invalid value for "+name+" in config file")
+		return strings.Join(values, listSeparator)
+
+	default:
+		m.Failf("invalid value for %s in config file (expected a scalar or a list)", name)
+		return ""
+	}
+}
+
+// override returns the override for a message, which is empty if the config file doesn't have one. The override
+// takes precedence over the options of the message.
+func (m *Module) override(message pgs.Message) messageOverride {
+	override := m.overrides[strings.TrimPrefix(message.FullyQualifiedName(), ".")]
+	if override.ID == "" {
+		override.ID = m.messageOptions(message).GetId()
+	}
+
+	if override.Required != "" {
+		override.Required = m.parseRequiredMode(string(override.Required))
+	}
+
+	return override
+}
+
+// applyOverride adjusts the object schema of a message according to its override.
+func (m *Module) applyOverride(message pgs.Message, schema *jsonschema.ObjectSchema) {
+	override := m.override(message)
+	if override.Title != "" {
+		schema.Title = override.Title
+	}
+
+	if override.AdditionalProperties != nil {
+		schema.AdditionalProperties = jsonschema.TrivialSchema(*override.AdditionalProperties)
+	}
+
+	for keyword, value := range override.Keywords {
+		schema.SetExtension(keyword, value)
+	}
+}
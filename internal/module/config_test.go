@@ -0,0 +1,162 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	config := writeConfig(t, `
+draft: 2020-12
+field_names: proto
+include:
+  - testproto.FieldNamesTest
+  - testproto.Recursive*
+`)
+
+	res := render(t, "config="+config)
+	require.ElementsMatch(t, []string{
+		"testproto/FieldNamesTest.schema.json",
+		"testproto/RecursiveTest.schema.json",
+		"testproto/RecursiveNode.schema.json",
+	}, filenames(t, res))
+
+	doc := document(t, res, "testproto/FieldNamesTest.schema.json")
+	require.Equal(t, "https://json-schema.org/draft/2020-12/schema", doc["$schema"])
+	require.Contains(t, lookup(t, doc, "properties"), "snake_case_field")
+
+	// Parameters given directly take precedence over the config file.
+	doc = document(t, render(t, "field_names=json,config="+config), "testproto/FieldNamesTest.schema.json")
+	require.Equal(t, "https://json-schema.org/draft/2020-12/schema", doc["$schema"])
+	require.Contains(t, lookup(t, doc, "properties"), "snakeCaseField")
+
+	output := renderFailure(t, "config="+writeConfig(t, "draft_version: 2020-12\n"))
+	require.Contains(t, output, `unknown parameter "draft_version"`)
+}
+
+// writeConfig writes a config file to a temporary directory and returns its path.
+func writeConfig(t *testing.T, config string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+	return path
+}
+
+func TestConfigMessageOverrides(t *testing.T) {
+	config := writeConfig(t, `
+messages:
+  testproto.FieldNamesTest:
+    id: urn:{package}:{message}
+    title: Field names
+    additionalProperties: true
+    keywords:
+      x-kind: names
+    required: all
+  testproto.RecursiveNode:
+    title: Node
+`)
+
+	res := render(t, "config="+config)
+	require.Equal(t, map[string]any{
+		"$id":                  "urn:testproto:FieldNamesTest",
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"title":                "Field names",
+		"type":                 "object",
+		"required":             []any{"snakeCaseField", "customName"},
+		"additionalProperties": true,
+		"properties": map[string]any{
+			"snakeCaseField": map[string]any{"type": "string"},
+			"customName":     map[string]any{"type": "string"},
+		},
+		"x-kind": "names",
+	}, document(t, res, "testproto/FieldNamesTest.schema.json"))
+
+	// Overrides also apply where the message is defined in the documents of other messages.
+	doc := document(t, res, "testproto/RecursiveTest.schema.json")
+	require.Equal(t, "Node", lookup(t, doc, "definitions", "testproto.RecursiveNode", "title"))
+
+	// Other messages are unaffected.
+	doc = document(t, res, "testproto/GenerateOptionTest.schema.json")
+	require.Equal(t, "https://protoc-gen-jsonschema.cerbos.dev/testproto/GenerateOptionTest.schema.json", doc["$id"])
+}
+
+func TestConfigPatches(t *testing.T) {
+	config := writeConfig(t, `
+patches:
+  - op: add
+    path: /x-generated
+    value: true
+messages:
+  testproto.FieldNamesTest:
+    patches:
+      - op: replace
+        path: /properties/customName
+        value: {type: string, minLength: 1}
+      - op: remove
+        path: /x-generated
+`)
+
+	res := render(t, "config="+config)
+	require.Equal(t, true, document(t, res, "testproto/GenerateOptionTest.schema.json")["x-generated"])
+
+	// The patches of a message are applied after the global patches.
+	doc := document(t, res, "testproto/FieldNamesTest.schema.json")
+	require.NotContains(t, doc, "x-generated")
+	require.Equal(t, map[string]any{"type": "string", "minLength": float64(1)}, lookup(t, doc, "properties", "customName"))
+
+	output := renderFailure(t, "config="+writeConfig(t, "patches:\n  - op: add\n    path: /x-generated\n"))
+	require.Contains(t, output, "operation 0 (add /x-generated): missing value member")
+}
+
+func TestConfigKeywordsReplaceGeneratedKeywords(t *testing.T) {
+	config := writeConfig(t, `
+messages:
+  testproto.TextOptionTest:
+    keywords:
+      description: Replaced.
+      type: [object, "null"]
+`)
+
+	raw := content(t, render(t, "config="+config), "testproto/TextOptionTest.schema.json")
+
+	// Decoding a document keeps the last of any duplicate keys, so the keys are counted as they're read.
+	counts := topLevelKeyCounts(t, raw)
+	require.Equal(t, 1, counts["description"])
+	require.Equal(t, 1, counts["type"])
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal([]byte(raw), &doc))
+	require.Equal(t, "Replaced.", doc["description"])
+	require.Equal(t, []any{"object", "null"}, doc["type"])
+}
+
+// topLevelKeyCounts counts the occurrences of each key of a JSON object, without looking inside its values.
+func topLevelKeyCounts(t *testing.T, raw string) map[string]int {
+	t.Helper()
+
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	token, err := decoder.Token()
+	require.NoError(t, err)
+	require.Equal(t, json.Delim('{'), token)
+
+	counts := make(map[string]int)
+	for decoder.More() {
+		token, err := decoder.Token()
+		require.NoError(t, err)
+		counts[token.(string)]++
+
+		var value json.RawMessage
+		require.NoError(t, decoder.Decode(&value))
+	}
+
+	return counts
+}
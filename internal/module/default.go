@@ -0,0 +1,125 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	pgs "github.com/lyft/protoc-gen-star/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	jsonschemapb "github.com/cerbos/protoc-gen-jsonschema/gen/pb/jsonschema"
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+// applyDefault annotates the schema of a field with its default value, if it has one. The default value is given by
+// the field's options, or else by its explicit default value, which only proto2 fields can declare.
+func (m *Module) applyDefault(field pgs.Field, options *jsonschemapb.FieldOptions, schema jsonschema.Schema) jsonschema.Schema {
+	m.debug("applyDefault")
+	if options.GetDefault() != "" {
+		return jsonschema.WithDefault(schema, m.parseDefaultOption(field, options.GetDefault()))
+	}
+
+	if field.Descriptor().DefaultValue == nil {
+		return schema
+	}
+
+	value := m.defaultValue(field.Type(), field.Descriptor().GetDefaultValue())
+	if value == nil {
+		return schema
+	}
+
+	return jsonschema.WithDefault(schema, value)
+}
+
+// parseDefaultOption parses the default value given as JSON in the options of a field. The value is checked by decoding
+// it into the field with protojson, so that it has to be a valid value of the field, and is then kept as it was given.
+func (m *Module) parseDefaultOption(field pgs.Field, value string) any {
+	if !json.Valid([]byte(value)) {
+		m.Failf("invalid default option %q (expected a JSON value)", value)
+	}
+
+	descriptor := m.fieldDescriptor(field)
+	if value == "null" && !acceptsNull(descriptor) {
+		m.Fail("invalid default option null, since protojson treats it as unset rather than as a value of the field")
+	}
+
+	key := descriptor.JSONName()
+	if descriptor.IsExtension() {
+		key = "[" + string(descriptor.FullName()) + "]"
+	}
+
+	container, err := json.Marshal(map[string]json.RawMessage{key: json.RawMessage(value)})
+	m.CheckErr(err, "failed to encode default option")
+
+	// The rest of the message is left unset, so its required fields aren't checked.
+	unmarshal := protojson.UnmarshalOptions{AllowPartial: true, Resolver: dynamicpb.NewTypes(m.descriptors)}
+	err = unmarshal.Unmarshal(container, dynamicpb.NewMessage(descriptor.ContainingMessage()))
+	m.CheckErr(err, "invalid default option of field")
+
+	return json.RawMessage(value)
+}
+
+// acceptsNull reports whether protojson decodes null as a value of the field, rather than treating it as unset.
+func acceptsNull(descriptor protoreflect.FieldDescriptor) bool {
+	switch {
+	case descriptor.IsList() || descriptor.IsMap():
+		return false
+	case descriptor.Message() != nil:
+		return descriptor.Message().FullName() == "google.protobuf.Value"
+	case descriptor.Enum() != nil:
+		return descriptor.Enum().FullName() == "google.protobuf.NullValue"
+	default:
+		return false
+	}
+}
+
+// defaultValue encodes a default value in the same way as protojson. Default values are recorded in descriptors as
+// text, with bytes C-escaped and enums given by name.
+func (m *Module) defaultValue(t pgs.FieldType, value string) any {
+	if t.IsEnum() {
+		for _, enumValue := range t.Enum().Values() {
+			if enumValue.Name().String() == value {
+				return m.enumValueJSON(enumValue)
+			}
+		}
+
+		m.debug("enum default is not a declared value", "value", value, "enum", t.Enum().FullyQualifiedName())
+		return nil
+	}
+
+	switch t.ProtoType() {
+	case pgs.BoolT:
+		return value == "true"
+
+	case pgs.StringT:
+		return value
+
+	case pgs.BytesT:
+		unescaped, err := strconv.Unquote(`"` + strings.ReplaceAll(value, `\'`, `'`) + `"`)
+		m.CheckErr(err, "failed to unescape bytes default value")
+		return base64.StdEncoding.EncodeToString([]byte(unescaped))
+
+	case pgs.FloatT, pgs.DoubleT:
+		bitSize := 64
+		if t.ProtoType() == pgs.FloatT {
+			bitSize = 32
+		}
+
+		number, err := strconv.ParseFloat(value, bitSize)
+		m.CheckErr(err, "failed to parse floating-point default value")
+		return m.floatExample(number, bitSize)
+
+	case pgs.Int64T, pgs.SFixed64, pgs.SInt64, pgs.Fixed64T, pgs.UInt64T:
+		return value
+
+	default:
+		return json.Number(value)
+	}
+}
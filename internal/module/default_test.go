@@ -0,0 +1,31 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultOption(t *testing.T) {
+	res := render(t, "")
+
+	properties := lookup(t, document(t, res, "testproto/DefaultOptionTest.schema.json"), "properties")
+	defaults := map[string]any{
+		"name":    "anonymous",
+		"limit":   float64(100),
+		"tags":    []any{"a", "b"},
+		"timeout": "30s",
+		"state":   "DUMMYENUM_SET",
+	}
+
+	for name, value := range defaults {
+		require.Equal(t, value, lookup(t, properties, name, "default"), name)
+	}
+
+	// Extensions are checked against the message they extend, which has a required field that the default leaves unset.
+	extension := lookup(t, document(t, res, "testproto/Proto2Test.schema.json"), "properties", "[testproto.extension_field]")
+	require.Equal(t, "extended", lookup(t, extension, "default"))
+}
@@ -0,0 +1,268 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	pgs "github.com/lyft/protoc-gen-star/v2"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+// groupMode selects how schemas are grouped into documents.
+type groupMode string
+
+const (
+	// groupByMessage generates a document for each message, whose root is the schema of the message.
+	groupByMessage groupMode = "message"
+	// groupByFile generates a document for each file, which defines the schemas of the messages declared in it.
+	groupByFile groupMode = "file"
+	// groupByPackage generates a document for each package, which defines the schemas of the messages declared in it.
+	groupByPackage groupMode = "package"
+)
+
+func (m *Module) parseGroupMode(value string) groupMode {
+	mode := groupMode(value)
+	switch mode {
+	case groupByMessage, groupByFile, groupByPackage:
+		return mode
+	default:
+		m.Failf("invalid value %q for group parameter (expected %q, %q or %q)", value, groupByMessage, groupByFile, groupByPackage)
+		return ""
+	}
+}
+
+// fileGroupMode returns how the documents for the messages declared in a file are grouped, which the file's options can
+// set to message or file instead of following the group parameter.
+func (m *Module) fileGroupMode(file pgs.File) groupMode {
+	value := m.fileOptions(file).GetGroup()
+	switch mode := groupMode(value); mode {
+	case "":
+		return m.groupMode
+	case groupByMessage, groupByFile:
+		return mode
+	default:
+		m.Failf("invalid value %q for group option of %s (expected %q or %q)", value, file.Name(), groupByMessage, groupByFile)
+		return ""
+	}
+}
+
+// selectMessages returns the messages declared in a file that schemas are generated for, leaving out those that are
+// excluded by the include and exclude parameters or skipped by their options, and those that aren't selected by their
+// options if the only_annotated parameter is set.
+func (m *Module) selectMessages(file pgs.File) []pgs.Message {
+	messages := file.AllMessages()
+	if m.topLevelOnly {
+		// Nested messages are still defined in the schemas of the messages that reference them.
+		messages = file.Messages()
+	}
+
+	var selected []pgs.Message
+	for _, message := range messages {
+		options := m.messageOptions(message)
+		if m.filter.matches(message) && !options.GetSkip() && (options.GetGenerate() || !m.onlyAnnotated) {
+			selected = append(selected, message)
+		}
+	}
+
+	return selected
+}
+
+func (m *Module) addMessageDocument(message pgs.Message) {
+	placeholders := messagePlaceholders(message)
+	filename := m.messageFilename(message, placeholders)
+	m.addDocument(m.defineMessage(message), []pgs.File{message.File()}, filename, m.override(message), placeholders)
+}
+
+// messageFilename returns the path of the file that the document of a message is written to, unless the options of the
+// message pin it. If the documents of messages in different packages would otherwise be written to the same file, their
+// names are prefixed with their packages.
+func (m *Module) messageFilename(message pgs.Message, placeholders map[string]string) string {
+	if filename := m.messageOptions(message).GetFilename(); filename != "" {
+		return m.expandTemplate("filename option of the message", filename, placeholders)
+	}
+
+	filename := m.documentFilename(strings.ReplaceAll(m.definitionName(message), ".", "/")+m.extension, placeholders)
+	if pkg := placeholders[placeholderPackage]; m.collidingFilenames[filename] && pkg != "" {
+		dir, name := path.Split(filename)
+		filename = dir + pkg + "." + name
+	}
+
+	return filename
+}
+
+// findCollidingFilenames returns the paths that the documents of more than one message would be written to.
+func (m *Module) findCollidingFilenames(targets map[string]pgs.File) map[string]bool {
+	counts := make(map[string]int)
+	for _, file := range targets {
+		if m.fileGroupMode(file) != groupByMessage {
+			continue
+		}
+
+		for _, message := range m.selectMessages(file) {
+			counts[m.messageFilename(message, messagePlaceholders(message))]++
+		}
+	}
+
+	colliding := make(map[string]bool)
+	for filename, count := range counts {
+		if count > 1 {
+			colliding[filename] = true
+		}
+	}
+
+	return colliding
+}
+
+func (m *Module) addFileDocument(file pgs.File, messages []pgs.Message) {
+	placeholders := filePlaceholders(file)
+	filename := m.documentFilename(placeholders[placeholderFile]+m.extension, placeholders)
+	m.addDocument(m.defineGroup(messages), []pgs.File{file}, filename, messageOverride{}, placeholders)
+}
+
+func (m *Module) addPackageDocument(pkg pgs.Package, targets map[string]pgs.File) {
+	var files []pgs.File
+	for _, file := range pkg.Files() {
+		if _, ok := targets[file.Name().String()]; ok && m.fileGroupMode(file) == groupByPackage {
+			files = append(files, file)
+		}
+	}
+
+	// Packages that only contain dependencies don't get documents.
+	if len(files) == 0 {
+		return
+	}
+
+	name := pkg.ProtoName().String()
+	m.enter("package", name)
+	defer m.leave()
+
+	if name == "" {
+		m.Fail("files without a package can't be grouped by package")
+	}
+
+	slices.SortFunc(files, func(a, b pgs.File) int {
+		return strings.Compare(a.Name().String(), b.Name().String())
+	})
+
+	options := m.fileOptions(files[0])
+	for _, file := range files[1:] {
+		if other := m.fileOptions(file); other.GetDraft() != options.GetDraft() || other.GetIdBase() != options.GetIdBase() {
+			m.Failf("%s and %s set different draft or id_base options, so they can't share a document", files[0].Name(), file.Name())
+		}
+	}
+
+	m.useFileOptions(options)
+
+	var messages []pgs.Message
+	for _, file := range files {
+		messages = append(messages, m.selectMessages(file)...)
+	}
+
+	if m.onlyAnnotated && len(messages) == 0 {
+		return
+	}
+
+	placeholders := packagePlaceholders(name)
+	filename := m.documentFilename(placeholders[placeholderPackagePath]+m.extension, placeholders)
+	m.addDocument(m.defineGroup(messages), files, filename, messageOverride{}, placeholders)
+}
+
+// documentFilename returns the path of the file that a document is written to, which is named by the filename template
+// if one is set.
+func (m *Module) documentFilename(filename string, placeholders map[string]string) string {
+	if m.filenameTemplate != "" {
+		return m.expandTemplate("filename_template parameter", m.filenameTemplate, placeholders)
+	}
+
+	return filename
+}
+
+// addDocument writes a schema document, generated from the given proto files, to a file. The override of the message
+// at the root of the document, if there is one, can replace its $id and add to the patches applied to it.
+func (m *Module) addDocument(schema jsonschema.NonTrivialSchema, sources []pgs.File, filename string, override messageOverride, placeholders map[string]string) {
+	if _, ok := m.filenames[filename]; ok {
+		m.Failf("more than one document would be written to %s (the filename_template parameter must distinguish them)", filename)
+	}
+
+	m.filenames[filename] = struct{}{}
+
+	idTemplate, idSource := m.idTemplate, "id_template parameter"
+	switch {
+	case override.ID != "":
+		idTemplate, idSource = override.ID, "id of the message"
+	case m.idBase != "":
+		idTemplate, idSource = m.idBase+placeholderFilename, "id_base option of the file"
+	}
+
+	placeholders[placeholderFilename] = filename
+	id := m.expandTemplate(idSource, idTemplate, placeholders)
+	schema.TopLevel(id, m.draft)
+	if m.formatAssertion {
+		schema.SetMetaSchema(m.metaSchemaID())
+	}
+
+	m.addProvenance(schema, sources)
+
+	patch := slices.Concat(m.patches, override.Patches)
+	content := m.encode(schema, filename, patch)
+	if m.outputTemplate != nil {
+		content = m.executeOutputTemplate(newOutputData(id, content, placeholders))
+	}
+
+	m.AddGeneratorFile(filename, content)
+}
+
+// metaSchemaFilename is the name of the file that the format-assertion meta-schema is written to, without the
+// extension.
+const metaSchemaFilename = "format-assertion"
+
+// metaSchemaID returns the $id of the format-assertion meta-schema, which is written alongside the generated schemas.
+func (m *Module) metaSchemaID() string {
+	return m.baseURL + metaSchemaFilename + m.extension
+}
+
+// addMetaSchema writes the format-assertion meta-schema, which the generated schemas refer to with $schema so that
+// validators enforce their formats.
+func (m *Module) addMetaSchema() {
+	if !m.formatAssertion {
+		return
+	}
+
+	filename := metaSchemaFilename + m.extension
+	m.AddGeneratorFile(filename, m.encode(jsonschema.NewFormatAssertionMetaSchema(m.metaSchemaID()), filename, nil))
+}
+
+// defineGroup returns a document that defines the schemas of several messages. Its root references the message named
+// by the root parameter, if it is one of them, and otherwise accepts anything.
+func (m *Module) defineGroup(messages []pgs.Message) jsonschema.NonTrivialSchema {
+	m.debug("defineGroup")
+	m.nestedUnderMessage = groupRoot{}
+	m.definitions = make(map[string]jsonschema.Schema)
+	m.recursive = make(map[string]bool)
+
+	schema := &jsonschema.GenericSchema{}
+	for _, message := range messages {
+		ref := m.messageRef(message)
+		if messagePlaceholders(message)[placeholderMessage] == m.root {
+			schema.AllOf = []jsonschema.NonTrivialSchema{ref}
+		}
+	}
+
+	schema.Define(m.definitions, m.draft)
+	m.definitions = nil
+	m.recursive = nil
+	m.nestedUnderMessage = nil
+	return schema
+}
+
+// groupRoot stands in for the message at the root of a document, when the root is a group of messages instead.
+type groupRoot struct{}
+
+func (groupRoot) FullyQualifiedName() string {
+	return ""
+}
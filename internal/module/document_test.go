@@ -0,0 +1,226 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	jsonschemapb "github.com/cerbos/protoc-gen-jsonschema/gen/pb/jsonschema"
+)
+
+func TestRecursiveDefinitions(t *testing.T) {
+	testCases := []struct {
+		draft       string
+		definitions string
+	}{
+		{draft: "draft-07", definitions: "definitions"},
+		{draft: "2019-09", definitions: "$defs"},
+		{draft: "2020-12", definitions: "$defs"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.draft, func(t *testing.T) {
+			doc := document(t, render(t, "draft="+tc.draft), "testproto/RecursiveTest.schema.json")
+			node := "#/" + tc.definitions + "/testproto.RecursiveNode"
+
+			// The root message refers to the document itself, and every other message is defined once.
+			require.Equal(t, map[string]any{"$ref": "#"}, lookup(t, doc, "properties", "parent"))
+			require.Equal(t, map[string]any{"$ref": node}, lookup(t, doc, "properties", "nodes", "items"))
+			require.Equal(t, map[string]any{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]any{
+					"children": map[string]any{"type": "array", "items": map[string]any{"$ref": node}},
+					"root":     map[string]any{"$ref": "#"},
+				},
+			}, lookup(t, doc, tc.definitions, "testproto.RecursiveNode"))
+		})
+	}
+}
+
+func TestIDTemplate(t *testing.T) {
+	testCases := []struct {
+		parameter string
+		filename  string
+		id        string
+	}{
+		{
+			parameter: "",
+			filename:  "testproto/FieldNamesTest.schema.json",
+			id:        "https://protoc-gen-jsonschema.cerbos.dev/testproto/FieldNamesTest.schema.json",
+		},
+		{
+			parameter: "baseurl=https://example.com/schemas",
+			filename:  "testproto/FieldNamesTest.schema.json",
+			id:        "https://example.com/schemas/testproto/FieldNamesTest.schema.json",
+		},
+		{
+			parameter: "id_template=urn:{package}:{message}:{file}:{version}",
+			filename:  "testproto/EmptyEmbeddedTest/EmbeddedExpression.schema.json",
+			id:        "urn:testproto:EmptyEmbeddedTest.EmbeddedExpression:testproto/testproto:",
+		},
+		{
+			parameter: "id_template=https://example.com/{filename}",
+			filename:  "testproto/other/NameCollisionTest.schema.json",
+			id:        "https://example.com/testproto/other/NameCollisionTest.schema.json",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			doc := document(t, render(t, tc.parameter), tc.filename)
+			require.Equal(t, tc.id, doc["$id"])
+		})
+	}
+}
+
+func TestFilenameTemplate(t *testing.T) {
+	res := render(t, "filename_template={file}/{package}.{message}.schema.json")
+
+	require.Subset(t, filenames(t, res), []string{
+		"testproto/testproto/testproto.EmptyEmbeddedTest.schema.json",
+		"testproto/testproto/testproto.EmptyEmbeddedTest.EmbeddedExpression.schema.json",
+		"testproto/other/other/testproto.other.NameCollisionTest.schema.json",
+	})
+
+	// The $id follows the path of the file.
+	doc := document(t, res, "testproto/testproto/testproto.FieldNamesTest.schema.json")
+	require.Equal(t, "https://protoc-gen-jsonschema.cerbos.dev/testproto/testproto/testproto.FieldNamesTest.schema.json", doc["$id"])
+}
+
+func TestPackagePath(t *testing.T) {
+	res := render(t, "filename_template=schemas/{package_path}/{message}.schema.json,id_template=urn:{package_path}")
+
+	require.Subset(t, filenames(t, res), []string{
+		"schemas/testproto/FieldNamesTest.schema.json",
+		"schemas/testproto/other/NameCollisionTest.schema.json",
+		"schemas/testproto/fileoptions/FileOptionsTest.schema.json",
+	})
+
+	doc := document(t, res, "schemas/testproto/other/NameCollisionTest.schema.json")
+	require.Equal(t, "urn:testproto/other", doc["$id"])
+}
+
+func TestGroupByFile(t *testing.T) {
+	res := render(t, "group=file")
+	require.ElementsMatch(t, []string{
+		"testproto/testproto.schema.json",
+		"testproto/editions.schema.json",
+		"testproto/proto2.schema.json",
+		"testproto/required.schema.json",
+		"testproto/other/other.schema.json",
+		"testproto/fileoptions/fileoptions.schema.json",
+	}, filenames(t, res))
+
+	// Without a root, the document only defines the messages declared in the file and those they reference.
+	doc := document(t, res, "testproto/editions.schema.json")
+	require.Equal(t, "https://protoc-gen-jsonschema.cerbos.dev/testproto/editions.schema.json", doc["$id"])
+	require.NotContains(t, doc, "allOf")
+	require.Subset(t, slices.Collect(maps.Keys(lookup(t, doc, "definitions").(map[string]any))), []string{"testproto.EditionsTest", "testproto.EditionsNested", "testproto.EditionsOpenEnum"})
+
+	doc = document(t, render(t, "group=file,root=RequiredModesTest"), "testproto/required.schema.json")
+	require.Equal(t, []any{map[string]any{"$ref": "#/definitions/testproto.RequiredModesTest"}}, lookup(t, doc, "allOf"))
+}
+
+func TestGroupByPackage(t *testing.T) {
+	res := render(t, "group=package,root=NameCollisionTest")
+	require.ElementsMatch(t, []string{
+		"testproto.schema.json",
+		"testproto/other.schema.json",
+		"testproto/fileoptions.schema.json",
+	}, filenames(t, res))
+
+	// The messages of every file in the package are defined in the same document.
+	doc := document(t, res, "testproto.schema.json")
+	definitions := lookup(t, doc, "definitions")
+	for _, name := range []string{"testproto.EditionsTest", "testproto.Proto2Test", "testproto.RequiredModesTest", "testproto.FieldNamesTest"} {
+		require.Contains(t, definitions, name)
+	}
+
+	// The root is the message with that name in each package, if there is one.
+	require.Equal(t, []any{map[string]any{"$ref": "#/definitions/testproto.NameCollisionTest"}}, lookup(t, doc, "allOf"))
+	other := document(t, res, "testproto/other.schema.json")
+	require.Equal(t, []any{map[string]any{"$ref": "#/definitions/testproto.other.NameCollisionTest"}}, lookup(t, other, "allOf"))
+	require.NotContains(t, document(t, res, "testproto/fileoptions.schema.json"), "allOf")
+}
+
+func TestAnchors(t *testing.T) {
+	for _, draft := range []string{"2019-09", "2020-12"} {
+		t.Run(draft, func(t *testing.T) {
+			doc := document(t, render(t, "anchors=true,draft="+draft), "testproto/RecursiveTest.schema.json")
+
+			require.Equal(t, map[string]any{"$ref": "#testproto.RecursiveNode"}, lookup(t, doc, "properties", "nodes", "items"))
+			require.Equal(t, "testproto.RecursiveNode", lookup(t, doc, "$defs", "testproto.RecursiveNode", "$anchor"))
+			require.Equal(t, map[string]any{"$ref": "#testproto.RecursiveNode"}, lookup(t, doc, "$defs", "testproto.RecursiveNode", "properties", "children", "items"))
+		})
+	}
+
+	require.Contains(t, renderFailure(t, "anchors=true"), "anchors parameter requires draft 2019-09 or 2020-12")
+}
+
+func TestDynamicRefs(t *testing.T) {
+	doc := document(t, render(t, "dynamic_refs=true,draft=2020-12"), "testproto/RecursiveTest.schema.json")
+
+	// References that close a cycle are dynamic, so that a schema extending a recursive message can extend every level.
+	require.Equal(t, "testproto.RecursiveTest", doc["$dynamicAnchor"])
+	require.Equal(t, map[string]any{"$dynamicRef": "#testproto.RecursiveTest"}, lookup(t, doc, "properties", "parent"))
+	require.Equal(t, map[string]any{"$ref": "#/$defs/testproto.RecursiveNode"}, lookup(t, doc, "properties", "nodes", "items"))
+
+	node := lookup(t, doc, "$defs", "testproto.RecursiveNode")
+	require.Equal(t, "testproto.RecursiveNode", lookup(t, node, "$dynamicAnchor"))
+	require.Equal(t, map[string]any{"$dynamicRef": "#testproto.RecursiveNode"}, lookup(t, node, "properties", "children", "items"))
+	require.Equal(t, map[string]any{"$dynamicRef": "#testproto.RecursiveTest"}, lookup(t, node, "properties", "root"))
+
+	// Messages that aren't recursive are unaffected.
+	doc = document(t, render(t, "dynamic_refs=true,draft=2020-12"), "testproto/FieldNamesTest.schema.json")
+	require.NotContains(t, doc, "$dynamicAnchor")
+
+	require.Contains(t, renderFailure(t, "dynamic_refs=true,draft=2019-09"), "dynamic_refs parameter requires draft 2020-12")
+}
+
+func TestMessageIDAndFilenameOptions(t *testing.T) {
+	// MessageOptionsTest sets its own id and filename, which take precedence over the parameters.
+	for _, parameter := range []string{"", "filename_template={message}.json,id_template=urn:{message}"} {
+		t.Run(parameter, func(t *testing.T) {
+			res := render(t, parameter)
+			require.NotContains(t, filenames(t, res), "testproto/MessageOptionsTest.schema.json")
+			require.NotContains(t, filenames(t, res), "MessageOptionsTest.json")
+
+			doc := document(t, res, "testproto/pinned/MessageOptionsTest.schema.json")
+			require.Equal(t, "https://example.com/schemas/MessageOptionsTest.json", doc["$id"])
+		})
+	}
+}
+
+func TestGroupOption(t *testing.T) {
+	group := func(value string) func(*pluginpb.CodeGeneratorRequest) {
+		return func(req *pluginpb.CodeGeneratorRequest) {
+			for _, file := range req.GetProtoFile() {
+				if file.GetName() == "testproto/fileoptions/fileoptions.proto" {
+					options, ok := proto.GetExtension(file.GetOptions(), jsonschemapb.E_File).(*jsonschemapb.FileOptions)
+					require.True(t, ok)
+					options.Group = value
+				}
+			}
+		}
+	}
+
+	// The option replaces the group parameter for the file.
+	names := filenames(t, renderEdited(t, "", group("file")))
+	require.Contains(t, names, "testproto/fileoptions/fileoptions.schema.json")
+	require.NotContains(t, names, "testproto/fileoptions/FileOptionsTest.schema.json")
+	require.Contains(t, names, "testproto/FieldNamesTest.schema.json")
+
+	// Files that set it are left out of the documents of their packages.
+	names = filenames(t, renderEdited(t, "group=package", group("message")))
+	require.Contains(t, names, "testproto/fileoptions/FileOptionsTest.schema.json")
+	require.NotContains(t, names, "testproto/fileoptions.schema.json")
+	require.Contains(t, names, "testproto.schema.json")
+}
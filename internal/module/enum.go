@@ -4,88 +4,357 @@
 package module
 
 import (
+	"math"
+	"strconv"
+	"strings"
+
 	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	pgs "github.com/lyft/protoc-gen-star/v2"
 
 	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
 )
 
-func (m *Module) defineEnum(enum pgs.Enum) *jsonschema.StringSchema {
+func (m *Module) defineEnum(enum pgs.Enum) jsonschema.NonTrivialSchema {
+	if !m.enumOpen(enum) {
+		return m.schemaForEnumValues(enum.Values())
+	}
+
+	// protobuf preserves unknown enum numbers, so an open enum accepts any int32.
+	unknown := jsonschema.NewIntegerSchema()
+	unknown.Minimum = jsonschema.Number(strconv.Itoa(math.MinInt32))
+	unknown.Maximum = jsonschema.Number(strconv.Itoa(math.MaxInt32))
+
+	if m.enumMode == enumAsNumber {
+		return unknown
+	}
+
+	return jsonschema.AnyOf(m.schemaForEnumValues(enum.Values()), unknown)
+}
+
+// enumOpen reports whether the enum's schema should accept undeclared numbers, which protobuf preserves for open enums.
+// The enum's options take precedence over the open_enums parameter.
+func (m *Module) enumOpen(enum pgs.Enum) bool {
+	options := m.enumOptions(enum)
+	if options.Open == nil {
+		return m.openEnums && !m.enumClosed(enum)
+	}
+
+	if options.GetOpen() && m.enumClosed(enum) {
+		m.Failf("open option of %s can't be true, since protobuf rejects undeclared numbers for closed enums", enum.FullyQualifiedName())
+	}
+
+	return options.GetOpen()
+}
+
+// enumMode selects how enum values are represented.
+type enumMode string
+
+const (
+	// enumAsName represents enum values by name, which is how protojson encodes them by default.
+	enumAsName enumMode = "name"
+	// enumAsNumber represents enum values by number, which is how protojson encodes them with UseEnumNumbers.
+	enumAsNumber enumMode = "number"
+	// enumAsNameOrNumber accepts either representation, as protojson does when parsing.
+	enumAsNameOrNumber enumMode = "both"
+)
+
+func (m *Module) parseEnumMode(value string) enumMode {
+	mode := enumMode(value)
+	switch mode {
+	case enumAsName, enumAsNumber, enumAsNameOrNumber:
+		return mode
+	default:
+		m.Failf("invalid value %q for enum parameter (expected %q, %q or %q)", value, enumAsName, enumAsNumber, enumAsNameOrNumber)
+		return ""
+	}
+}
+
+// schemaForEnumValues matches the JSON encodings of the given enum values.
+func (m *Module) schemaForEnumValues(values []pgs.EnumValue) jsonschema.NonTrivialSchema {
+	m.debug("schemaForEnumValues")
+	switch m.enumMode {
+	case enumAsNumber:
+		return m.schemaForEnumNumbers(values)
+
+	case enumAsNameOrNumber:
+		return jsonschema.AnyOf(m.schemaForEnumNames(values), m.schemaForEnumNumbers(values))
+
+	default:
+		return m.schemaForEnumNames(values)
+	}
+}
+
+func (m *Module) schemaForEnumNames(values []pgs.EnumValue) jsonschema.NonTrivialSchema {
+	m.debug("schemaForEnumNames")
+	if m.enumDescriptions {
+		var entries []jsonschema.NonTrivialSchema
+		for _, value := range values {
+			for _, name := range m.enumValueNames(value) {
+				entry := jsonschema.NewStringSchema()
+				entry.Const = jsonschema.String(name)
+				entry.Description = enumValueDescription(value)
+				entries = append(entries, entry)
+			}
+		}
+
+		return jsonschema.OneOf(entries...)
+	}
+
 	schema := jsonschema.NewStringSchema()
+	for _, value := range values {
+		schema.Enum = append(schema.Enum, m.enumValueNames(value)...)
+	}
 
-	for _, value := range enum.Values() {
-		schema.Enum = append(schema.Enum, value.Name().String())
+	if len(schema.Enum) == 1 {
+		schema.Const, schema.Enum = jsonschema.String(schema.Enum[0]), nil
 	}
 
 	return schema
 }
 
+func (m *Module) schemaForEnumNumbers(values []pgs.EnumValue) jsonschema.NonTrivialSchema {
+	m.debug("schemaForEnumNumbers")
+	schema := jsonschema.NewIntegerSchema()
+	var entries []jsonschema.NonTrivialSchema
+	seen := make(map[int32]struct{}, len(values))
+	for _, value := range values {
+		if _, ok := seen[value.Value()]; ok {
+			continue
+		}
+
+		seen[value.Value()] = struct{}{}
+		number := jsonschema.Number(strconv.Itoa(int(value.Value())))
+		schema.Enum = append(schema.Enum, number)
+
+		entry := jsonschema.NewIntegerSchema()
+		entry.Const = number
+		entry.Description = enumValueDescription(value)
+		entries = append(entries, entry)
+	}
+
+	if m.enumDescriptions {
+		return jsonschema.OneOf(entries...)
+	}
+
+	if len(schema.Enum) == 1 {
+		schema.Const, schema.Enum = schema.Enum[0], nil
+	}
+
+	return schema
+}
+
+// enumValueDescription returns the leading comment of an enum value, with the comment markers' indentation removed.
+func enumValueDescription(value pgs.EnumValue) string {
+	info := value.SourceCodeInfo()
+	if info == nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSpace(info.LeadingComments()), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// enumValueJSON returns the JSON encoding of an enum value.
+func (m *Module) enumValueJSON(value pgs.EnumValue) any {
+	if m.enumMode == enumAsNumber {
+		return value.Value()
+	}
+
+	return m.enumValueNames(value)[0]
+}
+
+// enumPrefixMode selects whether the prefix shared by the names of an enum's values is stripped.
+type enumPrefixMode string
+
+const (
+	// enumPrefixKeep uses the names as declared, which is the only form protojson accepts.
+	enumPrefixKeep enumPrefixMode = "keep"
+	// enumPrefixStrip strips the shared prefix, for APIs that expose short names.
+	enumPrefixStrip enumPrefixMode = "strip"
+	// enumPrefixBoth accepts either form.
+	enumPrefixBoth enumPrefixMode = "both"
+)
+
+func (m *Module) parseEnumPrefixMode(value string) enumPrefixMode {
+	mode := enumPrefixMode(value)
+	switch mode {
+	case enumPrefixKeep, enumPrefixStrip, enumPrefixBoth:
+		return mode
+	default:
+		m.Failf("invalid value %q for enum_prefix parameter (expected %q, %q or %q)", value, enumPrefixKeep, enumPrefixStrip, enumPrefixBoth)
+		return ""
+	}
+}
+
+// enumValueNames returns the names by which an enum value is represented, with the preferred name first.
+func (m *Module) enumValueNames(value pgs.EnumValue) []string {
+	name := value.Name().String()
+	if m.enumPrefixMode == enumPrefixKeep {
+		return []string{name}
+	}
+
+	short := strings.TrimPrefix(name, enumValuePrefix(value.Enum()))
+	switch {
+	case short == name:
+		return []string{name}
+	case m.enumPrefixMode == enumPrefixBoth:
+		return []string{short, name}
+	default:
+		return []string{short}
+	}
+}
+
+// enumValuePrefix returns the longest prefix ending in an underscore that is shared by the names of all of the enum's
+// values, such as "COLOR_" for COLOR_RED and COLOR_GREEN. Enums with a single value have no shared prefix.
+func enumValuePrefix(enum pgs.Enum) string {
+	values := enum.Values()
+	if len(values) < 2 {
+		return ""
+	}
+
+	prefix := values[0].Name().String()
+	for _, value := range values[1:] {
+		name := value.Name().String()
+		for !strings.HasPrefix(name, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	prefix = prefix[:strings.LastIndex(prefix, "_")+1]
+
+	// Stripping the prefix must leave every name non-empty.
+	for _, value := range values {
+		if value.Name().String() == prefix {
+			return ""
+		}
+	}
+
+	return prefix
+}
+
 func (m *Module) schemaForEnum(enum pgs.Enum, rules *validate.EnumRules) jsonschema.Schema {
-	m.Debug("schemaForEnum")
+	m.debug("schemaForEnum")
+	if isNullValue(enum) {
+		return jsonschema.NewNullSchema()
+	}
+
 	if rules != nil {
 		switch {
 		case rules.Const != nil:
 			return m.schemaForEnumConst(enum, rules.GetConst())
-		case len(rules.In) > 0:
-			return m.schemaForEnumIn(enum, rules.In)
-		case len(rules.NotIn) > 0:
-			return m.schemaForEnumNotIn(enum, rules.NotIn)
+		case len(rules.In) > 0 || len(rules.NotIn) > 0:
+			return m.schemaForEnumIn(enum, rules.In, rules.NotIn)
+		case rules.GetDefinedOnly():
+			return m.schemaForEnumDefinedOnly(enum)
 		}
 	}
 
 	return m.enumRef(enum)
 }
 
-func (m *Module) schemaForEnumConst(enum pgs.Enum, value int32) *jsonschema.StringSchema {
-	m.Debug("schemaForEnumConst")
-	schema := jsonschema.NewStringSchema()
-	schema.Const = jsonschema.String(m.lookUpEnumName(enum, value))
-
-	return schema
+// isNullValue reports whether the enum is google.protobuf.NullValue, which protojson encodes as null.
+func isNullValue(enum pgs.Enum) bool {
+	return enum.FullyQualifiedName() == ".google.protobuf.NullValue"
 }
 
-func (m *Module) schemaForEnumIn(enum pgs.Enum, values []int32) *jsonschema.StringSchema {
-	m.Debug("schemaForEnumIn")
-	schema := jsonschema.NewStringSchema()
-	for _, value := range values {
-		schema.Enum = append(schema.Enum, m.lookUpEnumName(enum, value))
+func (m *Module) schemaForEnumConst(enum pgs.Enum, value int32) jsonschema.Schema {
+	m.debug("schemaForEnumConst")
+	values := m.lookUpEnumValues(enum, func(v int32) bool { return v == value })
+	if len(values) == 0 {
+		m.debug("enum const is not a declared value", "value", value, "enum", enum.FullyQualifiedName())
+		return jsonschema.False
 	}
 
-	return schema
+	return m.schemaForEnumValues(values)
 }
 
-func (m *Module) schemaForEnumNotIn(enum pgs.Enum, values []int32) *jsonschema.StringSchema {
-	m.Debug("schemaForEnumNotIn")
-	exclude := make(map[int32]struct{}, len(values))
-	for _, v := range values {
+func (m *Module) schemaForEnumIn(enum pgs.Enum, in, notIn []int32) jsonschema.Schema {
+	m.debug("schemaForEnumIn")
+	include := make(map[int32]struct{}, len(in))
+	for _, v := range in {
+		include[v] = struct{}{}
+	}
+
+	exclude := make(map[int32]struct{}, len(notIn))
+	for _, v := range notIn {
 		exclude[v] = struct{}{}
 	}
 
-	schema := jsonschema.NewStringSchema()
-	for _, v := range enum.Values() {
-		value := v.Value()
-		if _, ok := exclude[value]; !ok {
-			schema.Enum = append(schema.Enum, v.Name().String())
+	if len(in) == 0 && m.enumOpen(enum) {
+		return m.schemaForOpenEnumNotIn(enum, notIn, exclude)
+	}
+
+	values := m.lookUpEnumValues(enum, func(v int32) bool {
+		if _, ok := exclude[v]; ok {
+			return false
+		}
+
+		if len(include) == 0 {
+			return true
 		}
+
+		_, ok := include[v]
+		return ok
+	})
+
+	if len(values) == 0 {
+		m.debug("enum rules exclude every declared value", "enum", enum.FullyQualifiedName())
+		return jsonschema.False
 	}
 
-	return schema
+	return m.schemaForEnumValues(values)
+}
+
+// schemaForOpenEnumNotIn accepts any value of an open enum, including undeclared numbers, apart from the excluded ones.
+func (m *Module) schemaForOpenEnumNotIn(enum pgs.Enum, notIn []int32, exclude map[int32]struct{}) jsonschema.Schema {
+	m.debug("schemaForOpenEnumNotIn")
+	numbers := jsonschema.NewIntegerSchema()
+	for _, v := range notIn {
+		numbers.Enum = append(numbers.Enum, jsonschema.Number(strconv.Itoa(int(v))))
+	}
+
+	excluded := []jsonschema.NonTrivialSchema{numbers}
+	values := m.lookUpEnumValues(enum, func(v int32) bool {
+		_, ok := exclude[v]
+		return ok
+	})
+
+	if len(values) > 0 && m.enumMode != enumAsNumber {
+		excluded = append(excluded, m.schemaForEnumNames(values))
+	}
+
+	return jsonschema.AllOf(m.enumRef(enum), jsonschema.Not(jsonschema.AnyOf(excluded...)))
+}
+
+func (m *Module) schemaForEnumDefinedOnly(enum pgs.Enum) jsonschema.Schema {
+	m.debug("schemaForEnumDefinedOnly")
+	if m.enumOpen(enum) {
+		return m.schemaForEnumValues(enum.Values())
+	}
+
+	// The enum definition only lists declared values, so it already rejects undefined ones.
+	return m.enumRef(enum)
 }
 
-func (m *Module) lookUpEnumName(enum pgs.Enum, value int32) string {
-	m.Debug("lookUpEnumName")
+// lookUpEnumValues returns the declared values (including aliases) whose numbers match.
+func (m *Module) lookUpEnumValues(enum pgs.Enum, match func(int32) bool) []pgs.EnumValue {
+	m.debug("lookUpEnumValues")
+	var values []pgs.EnumValue
 	for _, enumValue := range enum.Values() {
-		if enumValue.Value() == value {
-			return enumValue.Name().String()
+		if match(enumValue.Value()) {
+			values = append(values, enumValue)
 		}
 	}
 
-	m.Failf("unknown enum value %d", value)
-	return ""
+	return values
 }
 
 func (m *Module) enumRef(enum pgs.Enum) *jsonschema.GenericSchema {
-	m.Debug("enumRef")
+	m.debug("enumRef")
 	return m.ref(enum, func() jsonschema.Schema {
 		return m.defineEnum(enum)
 	})
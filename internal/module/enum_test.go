@@ -0,0 +1,141 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumModes(t *testing.T) {
+	testCases := []struct {
+		parameter string
+		schema    map[string]any
+		constant  map[string]any
+	}{
+		{parameter: "", schema: dummyEnumNames, constant: map[string]any{"type": "string", "const": "DUMMYENUM_SET"}},
+		{parameter: "enum=name", schema: dummyEnumNames, constant: map[string]any{"type": "string", "const": "DUMMYENUM_SET"}},
+		{parameter: "enum=number", schema: dummyEnumNumbers, constant: map[string]any{"type": "integer", "const": float64(2)}},
+		{
+			parameter: "enum=both",
+			schema:    map[string]any{"anyOf": []any{dummyEnumNames, dummyEnumNumbers}},
+			constant: map[string]any{"anyOf": []any{
+				map[string]any{"type": "string", "const": "DUMMYENUM_SET"},
+				map[string]any{"type": "integer", "const": float64(2)},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			require.Equal(t, tc.schema, dummyEnum(t, tc.parameter))
+
+			doc := document(t, render(t, tc.parameter), "testproto/EnumRulesTest.schema.json")
+			require.Equal(t, tc.constant, lookup(t, doc, "properties", "constField"))
+		})
+	}
+}
+
+var (
+	dummyEnumNames   = map[string]any{"type": "string", "enum": []any{"DUMMYENUM_UNSPECIFIED", "DUMMYENUM_UNSET", "DUMMYENUM_SET"}}
+	dummyEnumNumbers = map[string]any{"type": "integer", "enum": []any{float64(0), float64(1), float64(2)}}
+)
+
+// dummyEnum returns the definition of testproto.DummyEnum, which testproto.GenerateOptionTest refers to.
+func dummyEnum(t *testing.T, parameter string) any {
+	t.Helper()
+
+	doc := document(t, render(t, parameter), "testproto/GenerateOptionTest.schema.json")
+	require.Equal(t, "#/definitions/testproto.DummyEnum", lookup(t, doc, "properties", "state", "$ref"))
+	return lookup(t, doc, "definitions", "testproto.DummyEnum")
+}
+
+func TestEnumPrefix(t *testing.T) {
+	testCases := []struct {
+		parameter string
+		names     []any
+		constant  map[string]any
+	}{
+		{
+			parameter: "enum_prefix=keep",
+			names:     []any{"DUMMYENUM_UNSPECIFIED", "DUMMYENUM_UNSET", "DUMMYENUM_SET"},
+			constant:  map[string]any{"type": "string", "const": "DUMMYENUM_SET"},
+		},
+		{
+			parameter: "enum_prefix=strip",
+			names:     []any{"UNSPECIFIED", "UNSET", "SET"},
+			constant:  map[string]any{"type": "string", "const": "SET"},
+		},
+		{
+			parameter: "enum_prefix=both",
+			names:     []any{"UNSPECIFIED", "DUMMYENUM_UNSPECIFIED", "UNSET", "DUMMYENUM_UNSET", "SET", "DUMMYENUM_SET"},
+			constant:  map[string]any{"type": "string", "enum": []any{"SET", "DUMMYENUM_SET"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			require.Equal(t, map[string]any{"type": "string", "enum": tc.names}, dummyEnum(t, tc.parameter))
+
+			doc := document(t, render(t, tc.parameter), "testproto/EnumRulesTest.schema.json")
+			require.Equal(t, tc.constant, lookup(t, doc, "properties", "constField"))
+		})
+	}
+}
+
+func TestOpenEnums(t *testing.T) {
+	int32Range := map[string]any{"type": "integer", "minimum": float64(-2147483648), "maximum": float64(2147483647)}
+
+	require.Equal(t, map[string]any{"anyOf": []any{dummyEnumNames, int32Range}}, dummyEnum(t, "open_enums=true"))
+	require.Equal(t, int32Range, dummyEnum(t, "open_enums=true,enum=number"))
+
+	res := render(t, "open_enums=true")
+
+	// The defined_only rule rejects undeclared numbers, so the field stays closed.
+	rules := document(t, res, "testproto/EnumRulesTest.schema.json")
+	require.Equal(t, dummyEnumNames, lookup(t, rules, "properties", "definedOnlyField"))
+
+	// Closed enums reject undeclared numbers when parsing.
+	editions := document(t, res, "testproto/EditionsTest.schema.json")
+	require.Equal(t, map[string]any{
+		"type": "string",
+		"enum": []any{"EDITIONS_CLOSED_ENUM_UNSPECIFIED", "EDITIONS_CLOSED_ENUM_SET"},
+	}, lookup(t, editions, "definitions", "testproto.EditionsClosedEnum"))
+	require.Equal(t, int32Range, lookup(t, editions, "definitions", "testproto.EditionsOpenEnum", "anyOf", "1"))
+}
+
+func TestEnumDescriptions(t *testing.T) {
+	descriptions := []string{"The value has not been specified.", "The value is explicitly unset.", "The value is set."}
+	described := func(typ string, values ...any) map[string]any {
+		alternatives := make([]any, len(values))
+		for i, value := range values {
+			alternatives[i] = map[string]any{"description": descriptions[i], "type": typ, "const": value}
+		}
+
+		return map[string]any{"oneOf": alternatives}
+	}
+
+	names := described("string", "DUMMYENUM_UNSPECIFIED", "DUMMYENUM_UNSET", "DUMMYENUM_SET")
+	numbers := described("integer", float64(0), float64(1), float64(2))
+
+	require.Equal(t, names, dummyEnum(t, "enum_descriptions=true"))
+	require.Equal(t, numbers, dummyEnum(t, "enum_descriptions=true,enum=number"))
+	require.Equal(t, map[string]any{"anyOf": []any{names, numbers}}, dummyEnum(t, "enum_descriptions=true,enum=both"))
+}
+
+func TestEnumOpenOption(t *testing.T) {
+	int32Range := map[string]any{"type": "integer", "minimum": float64(-2147483648), "maximum": float64(2147483647)}
+	openNames := map[string]any{"type": "string", "enum": []any{"OPEN_ENUM_OPTION_TEST_UNSPECIFIED", "OPEN_ENUM_OPTION_TEST_SET"}}
+	closedNames := map[string]any{"type": "string", "enum": []any{"CLOSED_ENUM_OPTION_TEST_UNSPECIFIED", "CLOSED_ENUM_OPTION_TEST_SET"}}
+
+	// The option takes precedence over the open_enums parameter either way.
+	for _, parameter := range []string{"", "open_enums=true"} {
+		t.Run(parameter, func(t *testing.T) {
+			definitions := lookup(t, document(t, render(t, parameter), "testproto/EnumOptionsTest.schema.json"), "definitions")
+			require.Equal(t, map[string]any{"anyOf": []any{openNames, int32Range}}, lookup(t, definitions, "testproto.OpenEnumOptionTest"))
+			require.Equal(t, closedNames, lookup(t, definitions, "testproto.ClosedEnumOptionTest"))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+func (m *Module) applyExamples(element typed, rules *validate.FieldRules, schema jsonschema.Schema) jsonschema.Schema {
+	m.debug("applyExamples")
+	if rules == nil {
+		return schema
+	}
+
+	message := rules.ProtoReflect()
+	typeRules := message.WhichOneof(message.Descriptor().Oneofs().ByName("type"))
+	if typeRules == nil || typeRules.Kind() != protoreflect.MessageKind {
+		return schema
+	}
+
+	values := message.Get(typeRules).Message()
+	field := values.Descriptor().Fields().ByName("example")
+	if field == nil {
+		return schema
+	}
+
+	list := values.Get(field).List()
+	examples := make([]any, 0, list.Len())
+	for i := range list.Len() {
+		if example := m.example(element, field, list.Get(i)); example != nil {
+			examples = append(examples, example)
+		}
+	}
+
+	return jsonschema.WithExamples(schema, examples...)
+}
+
+// example encodes a value in the same way as protojson.
+func (m *Module) example(element typed, field protoreflect.FieldDescriptor, value protoreflect.Value) any {
+	if element.IsEnum() && isNullValue(element.Enum()) {
+		return nil
+	}
+
+	if element.IsEnum() {
+		values := m.lookUpEnumValues(element.Enum(), func(v int32) bool { return int64(v) == value.Int() })
+		if len(values) == 0 {
+			m.debug("enum example is not a declared value", "value", value.Int(), "enum", element.Enum().FullyQualifiedName())
+			return nil
+		}
+
+		return m.enumValueJSON(values[0])
+	}
+
+	switch field.Kind() {
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(value.Int(), 10)
+
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(value.Uint(), 10)
+
+	case protoreflect.FloatKind:
+		return m.floatExample(value.Float(), 32)
+
+	case protoreflect.DoubleKind:
+		return m.floatExample(value.Float(), 64)
+
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(value.Bytes())
+
+	case protoreflect.MessageKind:
+		data, err := protojson.Marshal(value.Message().Interface())
+		m.CheckErr(err, "failed to encode example")
+		return json.RawMessage(data)
+
+	default:
+		return value.Interface()
+	}
+}
+
+func (m *Module) floatExample(value float64, bitSize int) any {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "Infinity"
+	case math.IsInf(value, -1):
+		return "-Infinity"
+	default:
+		return json.Number(strconv.FormatFloat(value, 'g', -1, bitSize))
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"strings"
+
+	pgs "github.com/lyft/protoc-gen-star/v2"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// buildDescriptors builds runtime descriptors for the files in the request. protoc-gen-star derives presence from the
+// syntax of a file, which doesn't work for files using editions, so features such as presence and enum openness are
+// resolved by the protobuf runtime instead.
+func (m *Module) buildDescriptors(files []pgs.File) *protoregistry.Files {
+	m.debug("buildDescriptors")
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range files {
+		set.File = append(set.File, file.Descriptor())
+	}
+
+	registry, err := protodesc.NewFiles(set)
+	m.CheckErr(err, "failed to build descriptors")
+	return registry
+}
+
+func (m *Module) lookUpDescriptor(entity namedEntity) protoreflect.Descriptor {
+	name := protoreflect.FullName(strings.TrimPrefix(entity.FullyQualifiedName(), "."))
+	descriptor, err := m.descriptors.FindDescriptorByName(name)
+	m.CheckErr(err, "failed to look up descriptor")
+	return descriptor
+}
+
+func (m *Module) fieldDescriptor(field pgs.Field) protoreflect.FieldDescriptor {
+	descriptor, ok := m.lookUpDescriptor(field).(protoreflect.FieldDescriptor)
+	if !ok {
+		m.Failf("%s is not a field", field.FullyQualifiedName())
+	}
+
+	return descriptor
+}
+
+// fieldHasPresence reports whether the field distinguishes being unset from holding the zero value.
+func (m *Module) fieldHasPresence(field pgs.Field) bool {
+	return m.fieldDescriptor(field).HasPresence()
+}
+
+// fieldHasOptionalKeyword reports whether the field was declared optional. Editions have no optional keyword, and give
+// fields explicit presence by default, so only scalar fields that ask for explicit presence themselves are treated as
+// optional, which is how proto3 optional fields are migrated to editions. Message fields always have presence, whatever
+// their features say.
+func (m *Module) fieldHasOptionalKeyword(field pgs.Field) bool {
+	descriptor := m.fieldDescriptor(field)
+	if descriptor.ParentFile().Syntax() == protoreflect.Editions {
+		return descriptor.Cardinality() == protoreflect.Optional && descriptor.Message() == nil && descriptor.ContainingOneof() == nil &&
+			field.Descriptor().GetOptions().GetFeatures().GetFieldPresence() == descriptorpb.FeatureSet_EXPLICIT
+	}
+
+	return descriptor.HasOptionalKeyword()
+}
+
+// fieldRequiredByProtobuf reports whether the field is a proto2 required field, or has the LEGACY_REQUIRED presence
+// feature in a file using editions.
+func (m *Module) fieldRequiredByProtobuf(field pgs.Field) bool {
+	return m.fieldDescriptor(field).Cardinality() == protoreflect.Required
+}
+
+// enumClosed reports whether protobuf rejects undeclared numbers for the enum, as it does for proto2 enums and enums
+// with the CLOSED enum type feature.
+func (m *Module) enumClosed(enum pgs.Enum) bool {
+	descriptor, ok := m.lookUpDescriptor(enum).(protoreflect.EnumDescriptor)
+	if !ok {
+		m.Failf("%s is not an enum", enum.FullyQualifiedName())
+	}
+
+	return descriptor.IsClosed()
+}
@@ -0,0 +1,81 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"regexp"
+	"strings"
+
+	pgs "github.com/lyft/protoc-gen-star/v2"
+)
+
+// messageFilter selects the messages that schemas are generated for, by matching their fully-qualified names against
+// include and exclude patterns. Messages that are excluded are still defined in the schemas that reference them.
+type messageFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func (m *Module) parseMessageFilter(include, exclude string) messageFilter {
+	return messageFilter{
+		include: m.parseMessagePatterns("include", include),
+		exclude: m.parseMessagePatterns("exclude", exclude),
+	}
+}
+
+// parseMessagePatterns parses a list of patterns separated by semicolons. Each pattern is a glob, in which * matches
+// part of a name and ** matches any number of parts, or a regular expression if it is enclosed in slashes.
+func (m *Module) parseMessagePatterns(parameter, value string) []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, pattern := range strings.Split(value, listSeparator) {
+		if pattern == "" {
+			continue
+		}
+
+		expr := globToRegexp(pattern)
+		if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			expr = pattern[1 : len(pattern)-1]
+		}
+
+		re, err := regexp.Compile(expr)
+		m.CheckErr(err, "invalid pattern in "+parameter+" parameter")
+		patterns = append(patterns, re)
+	}
+
+	return patterns
+}
+
+func globToRegexp(glob string) string {
+	var builder strings.Builder
+	builder.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**"):
+			builder.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			builder.WriteString(`[^.]*`)
+		default:
+			builder.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+
+	builder.WriteString("$")
+	return builder.String()
+}
+
+func (f messageFilter) matches(message pgs.Message) bool {
+	name := strings.TrimPrefix(message.FullyQualifiedName(), ".")
+	return (len(f.include) == 0 || matchesAny(f.include, name)) && !matchesAny(f.exclude, name)
+}
+
+func matchesAny(patterns []*regexp.Regexp, name string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
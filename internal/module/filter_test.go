@@ -0,0 +1,91 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageFilter(t *testing.T) {
+	testCases := []struct {
+		parameter string
+		filenames []string
+	}{
+		{
+			parameter: "include=testproto.other.*",
+			filenames: []string{"testproto/other/NameCollisionTest.schema.json"},
+		},
+		{
+			parameter: "include=testproto.EmptyEmbeddedTest*",
+			filenames: []string{"testproto/EmptyEmbeddedTest.schema.json"},
+		},
+		{
+			parameter: "include=testproto.EmptyEmbeddedTest.**",
+			filenames: []string{
+				"testproto/EmptyEmbeddedTest/EmbeddedExpression.schema.json",
+				"testproto/EmptyEmbeddedTest/EmbeddedExpression/EmbeddedOperand.schema.json",
+			},
+		},
+		{
+			parameter: "include=/Recursive/;testproto.other.**",
+			filenames: []string{
+				"testproto/RecursiveNode.schema.json",
+				"testproto/RecursiveTest.schema.json",
+				"testproto/other/NameCollisionTest.schema.json",
+			},
+		},
+		{
+			parameter: "include=testproto.Empty*,exclude=/Embedded/;testproto.EmptyMapRulesTest",
+			filenames: []string{
+				"testproto/EmptyBoolRulesTest.schema.json",
+				"testproto/EmptyByteRulesTest.schema.json",
+				"testproto/EmptyEnumRulesTest.schema.json",
+				"testproto/EmptyFieldConstraintTest.schema.json",
+				"testproto/EmptyOneOfRulesTest.schema.json",
+				"testproto/EmptyStringRulesTest.schema.json",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			require.ElementsMatch(t, tc.filenames, filenames(t, render(t, tc.parameter)))
+		})
+	}
+}
+
+func TestExcludedMessagesAreStillDefined(t *testing.T) {
+	res := render(t, "exclude=testproto.EmptyBoolRulesTest")
+	require.NotContains(t, filenames(t, res), "testproto/EmptyBoolRulesTest.schema.json")
+
+	doc := document(t, res, "testproto/EmptyOneOfRulesTest.schema.json")
+	require.Equal(t, map[string]any{"$ref": "#/definitions/testproto.EmptyBoolRulesTest"}, lookup(t, doc, "allOf", "0", "properties", "boolField"))
+	require.Contains(t, lookup(t, doc, "definitions"), "testproto.EmptyBoolRulesTest")
+}
+
+func TestTopLevelOnly(t *testing.T) {
+	all := filenames(t, render(t, ""))
+	topLevel := filenames(t, render(t, "top_level_only=true"))
+
+	require.Contains(t, all, "testproto/EmptyEmbeddedTest/EmbeddedExpression.schema.json")
+	require.Contains(t, all, "testproto/EmptyEmbeddedTest/EmbeddedExpression/EmbeddedOperand.schema.json")
+	require.Subset(t, all, topLevel)
+	require.Len(t, topLevel, len(all)-2)
+
+	// Nested messages are still defined in the schemas that reference them.
+	doc := document(t, render(t, "top_level_only=true"), "testproto/EmptyEmbeddedTest.schema.json")
+	require.Contains(t, lookup(t, doc, "definitions"), "testproto.EmptyEmbeddedTest.EmbeddedExpression")
+}
+
+func TestSkipOption(t *testing.T) {
+	res := render(t, "")
+	require.NotContains(t, filenames(t, res), "testproto/SkippedMessageTest.schema.json")
+
+	// A skipped message doesn't get its own document, but is still defined where it's referenced.
+	doc := document(t, res, "testproto/SkippedMessageReferenceTest.schema.json")
+	require.Equal(t, map[string]any{"$ref": "#/definitions/testproto.SkippedMessageTest"}, lookup(t, doc, "properties", "skipped"))
+	require.Equal(t, map[string]any{"type": "string"}, lookup(t, doc, "definitions", "testproto.SkippedMessageTest", "properties", "name"))
+}
@@ -0,0 +1,129 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+// formatMode selects how the formats of strings are expressed.
+type formatMode string
+
+const (
+	// formatAsKeyword uses the format keyword, which many validators only treat as an annotation.
+	formatAsKeyword formatMode = "keyword"
+	// formatAsPattern replaces the format keyword with patterns matching the strings that protovalidate accepts.
+	formatAsPattern formatMode = "pattern"
+)
+
+func (m *Module) parseFormatMode(value string) formatMode {
+	mode := formatMode(value)
+	switch mode {
+	case formatAsKeyword, formatAsPattern:
+		return mode
+	default:
+		m.Failf("invalid value %q for formats parameter (expected %q or %q)", value, formatAsKeyword, formatAsPattern)
+		return ""
+	}
+}
+
+// Patterns matching the strings that protovalidate accepts for each format.
+const (
+	// emailPattern is the definition of a valid email address from the HTML standard.
+	emailPattern   = "^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@" + hostnameLabel + `(?:\.` + hostnameLabel + `)*$`
+	hostnameLabel  = `[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?`
+	hostnameLabels = `^` + hostnameLabel + `(?:\.` + hostnameLabel + `)*\.?$`
+	// hostnameNumeric matches hostnames whose last label is only digits, which aren't valid.
+	hostnameNumeric = `(?:^|\.)[0-9]+\.?$`
+	ipv4Pattern     = `^` + ipv4Address + `$`
+	ipv6Zone        = `(?:%.+)?`
+	// These patterns check that URIs start with a scheme and only contain characters that are allowed in URIs, but
+	// not the structure of the rest of the URI.
+	uriCharacters       = `(?:[a-zA-Z0-9\-._~!$&'()*+,;=:@/?#\[\]]|%[0-9a-fA-F]{2})*`
+	uriPattern          = `^[a-zA-Z][a-zA-Z0-9+.\-]*:` + uriCharacters + `$`
+	uriReferencePattern = `^` + uriCharacters + `$`
+	// Hostnames can be 253 characters long, excluding the optional trailing dot.
+	maxHostnameLength = 253
+)
+
+// ipv6Pattern matches IPv6 addresses in any of the forms that RFC 4291 allows, with an optional zone.
+var ipv6Pattern = `^(?:` + ipv6AddressPattern() + `)` + ipv6Zone + `$`
+
+// ipv6AddressPattern returns a pattern matching an IPv6 address, made up of eight groups of hexadecimal digits, the
+// last two of which can be written as an IPv4 address. Consecutive groups of zeros can be replaced by "::" once.
+func ipv6AddressPattern() string {
+	const group = `[0-9a-fA-F]{1,4}`
+	alternatives := []string{
+		fmt.Sprintf(`(?:%s:){7}%s`, group, group),
+		fmt.Sprintf(`(?:%s:){6}%s`, group, ipv4Address),
+	}
+
+	// "::" stands for at least one group, so the groups on either side of it add up to seven at most.
+	for before := 0; before <= 7; before++ {
+		prefix := "::"
+		if before > 0 {
+			prefix = fmt.Sprintf(`(?:%s:){%d}%s::`, group, before-1, group)
+		}
+
+		after := 7 - before
+		if after == 0 {
+			alternatives = append(alternatives, prefix)
+			continue
+		}
+
+		alternatives = append(alternatives, fmt.Sprintf(`%s(?:(?:%s:){0,%d}%s)?`, prefix, group, after-1, group))
+		if after >= 2 {
+			alternatives = append(alternatives, fmt.Sprintf(`%s(?:%s:){0,%d}%s`, prefix, group, after-2, ipv4Address))
+		}
+	}
+
+	return strings.Join(alternatives, "|")
+}
+
+// applyFormat sets the format of a string schema. In pattern mode, the format is replaced with schemas matching the
+// strings that protovalidate accepts, which are added to the schemas that the string must match.
+func (m *Module) applyFormat(schema *jsonschema.StringSchema, format jsonschema.StringFormat, schemas []jsonschema.NonTrivialSchema) []jsonschema.NonTrivialSchema {
+	if m.formatMode != formatAsPattern {
+		schema.Format = format
+		return schemas
+	}
+
+	switch format {
+	case jsonschema.StringFormatEmail:
+		return append(schemas, stringPattern(emailPattern))
+
+	case jsonschema.StringFormatHostname:
+		numeric := stringPattern(hostnameNumeric)
+		short := jsonschema.NewStringSchema()
+		short.MaxLength = jsonschema.Size(maxHostnameLength)
+		trailingDot := stringPattern(`\.$`)
+		trailingDot.MaxLength = jsonschema.Size(maxHostnameLength + 1)
+		return append(schemas, stringPattern(hostnameLabels), jsonschema.Not(numeric), jsonschema.AnyOf(short, trailingDot))
+
+	case jsonschema.StringFormatIPv4:
+		return append(schemas, stringPattern(ipv4Pattern))
+
+	case jsonschema.StringFormatIPv6:
+		return append(schemas, stringPattern(ipv6Pattern))
+
+	case jsonschema.StringFormatURI:
+		return append(schemas, stringPattern(uriPattern))
+
+	case jsonschema.StringFormatURIReference:
+		return append(schemas, stringPattern(uriReferencePattern))
+
+	default:
+		// The other formats are always backed up by patterns, so they're just left out.
+		return schemas
+	}
+}
+
+func stringPattern(pattern string) *jsonschema.StringSchema {
+	schema := jsonschema.NewStringSchema()
+	schema.Pattern = pattern
+	return schema
+}
@@ -0,0 +1,66 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatsAsKeywords(t *testing.T) {
+	for _, parameter := range []string{"", "formats=keyword"} {
+		t.Run(parameter, func(t *testing.T) {
+			res := render(t, parameter)
+
+			doc := document(t, res, "testproto/StringRulesTest.schema.json")
+			require.Equal(t, map[string]any{"type": "string", "format": "email"}, lookup(t, doc, "properties", "looseEmailField"))
+
+			doc = document(t, res, "testproto/MapRulesTest.schema.json")
+			require.Equal(t, map[string]any{"type": "string", "format": "hostname"}, lookup(t, doc, "properties", "hosts", "propertyNames"))
+		})
+	}
+}
+
+func TestFormatsAsPatterns(t *testing.T) {
+	res := render(t, "formats=pattern")
+
+	doc := document(t, res, "testproto/StringRulesTest.schema.json")
+	email := lookup(t, doc, "properties", "looseEmailField", "allOf").([]any)
+	require.Len(t, email, 2)
+	require.Equal(t, map[string]any{"type": "string"}, email[0])
+	requireMatches(t, lookup(t, email[1], "pattern"),
+		[]string{"alice@example.com", "a.b+c@sub.example.org", "x@localhost"},
+		[]string{"", "alice", "@example.com", "alice@", "alice@-example.com", "alice@example..com", "Alice <alice@example.com>"},
+	)
+
+	doc = document(t, res, "testproto/MapRulesTest.schema.json")
+	hostname := lookup(t, doc, "properties", "hosts", "propertyNames", "allOf").([]any)
+	require.Len(t, hostname, 4)
+	requireMatches(t, lookup(t, hostname[1], "pattern"),
+		[]string{"example.com", "a-b.example.com.", "localhost"},
+		[]string{"", "-example.com", "example-.com", "exa_mple.com", "example..com"},
+	)
+
+	// Hostnames whose last label is numeric would be mistaken for IP addresses.
+	requireMatches(t, lookup(t, hostname[2], "not", "pattern"), []string{"127.0.0.1", "example.123"}, []string{"example.com", "123.example"})
+}
+
+func TestFormatAssertion(t *testing.T) {
+	res := render(t, "format_assertion=true,draft=2020-12")
+	metaSchemaID := "https://protoc-gen-jsonschema.cerbos.dev/format-assertion.schema.json"
+
+	// The documents are validated against a meta-schema that requires the format-assertion vocabulary.
+	doc := document(t, res, "testproto/FormatOptionTest.schema.json")
+	require.Equal(t, metaSchemaID, doc["$schema"])
+
+	metaSchema := document(t, res, "format-assertion.schema.json")
+	require.Equal(t, metaSchemaID, metaSchema["$id"])
+	require.Equal(t, "https://json-schema.org/draft/2020-12/schema", metaSchema["$schema"])
+	require.Equal(t, true, lookup(t, metaSchema, "$vocabulary", "https://json-schema.org/draft/2020-12/vocab/format-assertion"))
+	require.Contains(t, lookup(t, metaSchema, "allOf"), map[string]any{"$ref": "https://json-schema.org/draft/2020-12/meta/format-assertion"})
+
+	require.NotContains(t, filenames(t, render(t, "draft=2020-12")), "format-assertion.schema.json")
+	require.Contains(t, renderFailure(t, "format_assertion=true"), "format_assertion parameter requires draft 2020-12")
+}
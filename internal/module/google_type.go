@@ -0,0 +1,195 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"fmt"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	pgs "github.com/lyft/protoc-gen-star/v2"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+const googleTypePackage = "google.type"
+
+// googleType is one of the common types from the google.type package, which are defined with constraints on their
+// fields that are documented but not expressed as validation rules.
+type googleType string
+
+const (
+	googleTypeColor         googleType = "Color"
+	googleTypeDate          googleType = "Date"
+	googleTypeInterval      googleType = "Interval"
+	googleTypeLatLng        googleType = "LatLng"
+	googleTypeMoney         googleType = "Money"
+	googleTypePostalAddress googleType = "PostalAddress"
+	googleTypeTimeOfDay     googleType = "TimeOfDay"
+)
+
+func (t googleType) FullyQualifiedName() string {
+	return fmt.Sprintf(".%s.%s", googleTypePackage, t)
+}
+
+// schemaForGoogleType returns a reference to the definition of a common type, or nil if the message isn't one.
+func (m *Module) schemaForGoogleType(message pgs.Message) jsonschema.Schema {
+	m.debug("schemaForGoogleType")
+	if message.Package().ProtoName().String() != googleTypePackage {
+		return nil
+	}
+
+	var define func() jsonschema.Schema
+	name := googleType(message.Name().String())
+	switch name {
+	case googleTypeColor:
+		define = m.defineColor
+	case googleTypeDate:
+		define = m.defineDate
+	case googleTypeInterval:
+		define = m.defineInterval
+	case googleTypeLatLng:
+		define = m.defineLatLng
+	case googleTypeMoney:
+		define = m.defineMoney
+	case googleTypePostalAddress:
+		define = m.definePostalAddress
+	case googleTypeTimeOfDay:
+		define = m.defineTimeOfDay
+	default:
+		return nil
+	}
+
+	return m.ref(name, define)
+}
+
+func (m *Module) defineColor() jsonschema.Schema {
+	m.debug("defineColor")
+	schema := jsonschema.NewClosedObjectSchema()
+	schema.Title = m.title("Color")
+	schema.Description = "A color in the RGBA color space."
+	m.addKnownProperty(schema, googleTypeColor, "red", m.schemaForScalar(pgs.FloatT, floatRange(0, 1)), false)
+	m.addKnownProperty(schema, googleTypeColor, "green", m.schemaForScalar(pgs.FloatT, floatRange(0, 1)), false)
+	m.addKnownProperty(schema, googleTypeColor, "blue", m.schemaForScalar(pgs.FloatT, floatRange(0, 1)), false)
+	m.addKnownProperty(schema, googleTypeColor, "alpha", jsonschema.Nullable(m.schemaForScalar(pgs.FloatT, floatRange(0, 1))), false)
+	return schema
+}
+
+func (m *Module) defineDate() jsonschema.Schema {
+	m.debug("defineDate")
+	schema := jsonschema.NewClosedObjectSchema()
+	schema.Title = m.title("Date")
+	schema.Description = "A whole or partial calendar date, where zero values stand for an unspecified year, month or day."
+	m.addKnownProperty(schema, googleTypeDate, "year", m.schemaForScalar(pgs.Int32T, int32Range(0, 9999)), false)
+	m.addKnownProperty(schema, googleTypeDate, "month", m.schemaForScalar(pgs.Int32T, int32Range(0, 12)), false)
+	m.addKnownProperty(schema, googleTypeDate, "day", m.schemaForScalar(pgs.Int32T, int32Range(0, 31)), false)
+	return schema
+}
+
+func (m *Module) defineInterval() jsonschema.Schema {
+	m.debug("defineInterval")
+	schema := jsonschema.NewClosedObjectSchema()
+	schema.Title = m.title("Interval")
+	schema.Description = "A time interval, encoded as an inclusive start timestamp and an exclusive end timestamp."
+	m.addKnownProperty(schema, googleTypeInterval, "start_time", m.schemaForTimestamp(nil), false)
+	m.addKnownProperty(schema, googleTypeInterval, "end_time", m.schemaForTimestamp(nil), false)
+	return schema
+}
+
+func (m *Module) defineLatLng() jsonschema.Schema {
+	m.debug("defineLatLng")
+	schema := jsonschema.NewClosedObjectSchema()
+	schema.Title = m.title("LatLng")
+	schema.Description = "A latitude/longitude pair in degrees, conforming to the WGS84 standard."
+	m.addKnownProperty(schema, googleTypeLatLng, "latitude", m.schemaForScalar(pgs.DoubleT, doubleRange(-90, 90)), false)
+	m.addKnownProperty(schema, googleTypeLatLng, "longitude", m.schemaForScalar(pgs.DoubleT, doubleRange(-180, 180)), false)
+	return schema
+}
+
+func (m *Module) defineMoney() jsonschema.Schema {
+	m.debug("defineMoney")
+	currencyCode := jsonschema.NewStringSchema()
+	currencyCode.Pattern = `^[A-Z]{3}$`
+
+	schema := jsonschema.NewClosedObjectSchema()
+	schema.Title = m.title("Money")
+	schema.Description = "An amount of money with its three-letter ISO 4217 currency code."
+	m.addKnownProperty(schema, googleTypeMoney, "currency_code", currencyCode, false)
+	m.addKnownProperty(schema, googleTypeMoney, "units", m.schemaForScalar(pgs.Int64T, nil), false)
+	m.addKnownProperty(schema, googleTypeMoney, "nanos", m.schemaForScalar(pgs.Int32T, int32Range(-999_999_999, 999_999_999)), false)
+	return schema
+}
+
+func (m *Module) definePostalAddress() jsonschema.Schema {
+	m.debug("definePostalAddress")
+	lines := jsonschema.NewArraySchema()
+	lines.Items = jsonschema.NewStringSchema()
+
+	regionCode := jsonschema.NewStringSchema()
+	regionCode.Pattern = `^[A-Z]{2}$`
+
+	schema := jsonschema.NewClosedObjectSchema()
+	schema.Title = m.title("PostalAddress")
+	schema.Description = "A postal address, such as for postal delivery or payments addresses."
+	m.addKnownProperty(schema, googleTypePostalAddress, "revision", m.schemaForScalar(pgs.Int32T, int32Range(0, 0)), false)
+	m.addKnownProperty(schema, googleTypePostalAddress, "region_code", regionCode, true)
+	m.addKnownProperty(schema, googleTypePostalAddress, "language_code", jsonschema.NewStringSchema(), false)
+	m.addKnownProperty(schema, googleTypePostalAddress, "postal_code", jsonschema.NewStringSchema(), false)
+	m.addKnownProperty(schema, googleTypePostalAddress, "sorting_code", jsonschema.NewStringSchema(), false)
+	m.addKnownProperty(schema, googleTypePostalAddress, "administrative_area", jsonschema.NewStringSchema(), false)
+	m.addKnownProperty(schema, googleTypePostalAddress, "locality", jsonschema.NewStringSchema(), false)
+	m.addKnownProperty(schema, googleTypePostalAddress, "sublocality", jsonschema.NewStringSchema(), false)
+	m.addKnownProperty(schema, googleTypePostalAddress, "address_lines", lines, false)
+	m.addKnownProperty(schema, googleTypePostalAddress, "recipients", lines, false)
+	m.addKnownProperty(schema, googleTypePostalAddress, "organization", jsonschema.NewStringSchema(), false)
+	return schema
+}
+
+func (m *Module) defineTimeOfDay() jsonschema.Schema {
+	m.debug("defineTimeOfDay")
+	schema := jsonschema.NewClosedObjectSchema()
+	schema.Title = m.title("TimeOfDay")
+	schema.Description = "A time of day, where 24:00:00 may stand for the end of the day and 60 seconds for a leap second."
+	m.addKnownProperty(schema, googleTypeTimeOfDay, "hours", m.schemaForScalar(pgs.Int32T, int32Range(0, 24)), false)
+	m.addKnownProperty(schema, googleTypeTimeOfDay, "minutes", m.schemaForScalar(pgs.Int32T, int32Range(0, 59)), false)
+	m.addKnownProperty(schema, googleTypeTimeOfDay, "seconds", m.schemaForScalar(pgs.Int32T, int32Range(0, 60)), false)
+	m.addKnownProperty(schema, googleTypeTimeOfDay, "nanos", m.schemaForScalar(pgs.Int32T, int32Range(0, 999_999_999)), false)
+	return schema
+}
+
+// addKnownProperty adds a property to the schema of a common type, given the original name of the field.
+func (m *Module) addKnownProperty(schema *jsonschema.ObjectSchema, t googleType, name string, value jsonschema.Schema, required bool) {
+	field := knownField{message: t, name: name}
+	schema.AllOf = append(schema.AllOf, m.addProperty(schema, field, m.knownPropertyNames(name), value, required)...)
+}
+
+// knownField identifies a field of one of the types whose schemas are defined by hand.
+type knownField struct {
+	message namedEntity
+	name    string
+}
+
+func (f knownField) FullyQualifiedName() string {
+	return f.message.FullyQualifiedName() + "." + f.name
+}
+
+func int32Range(minimum, maximum int32) *validate.FieldRules {
+	return &validate.FieldRules{Type: &validate.FieldRules_Int32{Int32: &validate.Int32Rules{
+		GreaterThan: &validate.Int32Rules_Gte{Gte: minimum},
+		LessThan:    &validate.Int32Rules_Lte{Lte: maximum},
+	}}}
+}
+
+func floatRange(minimum, maximum float32) *validate.FieldRules {
+	return &validate.FieldRules{Type: &validate.FieldRules_Float{Float: &validate.FloatRules{
+		GreaterThan: &validate.FloatRules_Gte{Gte: minimum},
+		LessThan:    &validate.FloatRules_Lte{Lte: maximum},
+	}}}
+}
+
+func doubleRange(minimum, maximum float64) *validate.FieldRules {
+	return &validate.FieldRules{Type: &validate.FieldRules_Double{Double: &validate.DoubleRules{
+		GreaterThan: &validate.DoubleRules_Gte{Gte: minimum},
+		LessThan:    &validate.DoubleRules_Lte{Lte: maximum},
+	}}}
+}
@@ -0,0 +1,29 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoogleTypes(t *testing.T) {
+	doc := document(t, render(t, ""), "testproto/GoogleTypeTest.schema.json")
+
+	require.Equal(t, map[string]any{"$ref": "#/definitions/google.type.Date"}, lookup(t, doc, "properties", "date"))
+	require.Equal(t, map[string]any{"$ref": "#/definitions/google.type.Money"}, lookup(t, doc, "properties", "prices", "items"))
+
+	require.Equal(t, map[string]any{
+		"year":  map[string]any{"type": "integer", "minimum": float64(0), "maximum": float64(9999)},
+		"month": map[string]any{"type": "integer", "minimum": float64(0), "maximum": float64(12)},
+		"day":   map[string]any{"type": "integer", "minimum": float64(0), "maximum": float64(31)},
+	}, lookup(t, doc, "definitions", "google.type.Date", "properties"))
+	require.Equal(t, map[string]any{"type": "number", "minimum": float64(-90), "maximum": float64(90)}, lookup(t, doc, "definitions", "google.type.LatLng", "properties", "latitude"))
+
+	money := lookup(t, doc, "definitions", "google.type.Money").(map[string]any)
+	require.Equal(t, false, money["additionalProperties"])
+	require.Equal(t, map[string]any{"type": "string", "pattern": "^[A-Z]{3}$"}, lookup(t, money, "properties", "currencyCode"))
+	require.Equal(t, map[string]any{"type": "integer", "minimum": float64(-999999999), "maximum": float64(999999999)}, lookup(t, money, "properties", "nanos"))
+}
@@ -0,0 +1,96 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	pgs "github.com/lyft/protoc-gen-star/v2"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+type typed interface {
+	ProtoType() pgs.ProtoType
+	IsEmbed() bool
+	IsEnum() bool
+	Enum() pgs.Enum
+}
+
+func (m *Module) applyIgnore(zero jsonschema.NonTrivialSchema, rules *validate.FieldRules, schema jsonschema.Schema) jsonschema.Schema {
+	m.debug("applyIgnore")
+	if zero == nil || rules.GetIgnore() != validate.Ignore_IGNORE_IF_ZERO_VALUE {
+		return schema
+	}
+
+	constrained, ok := schema.(jsonschema.NonTrivialSchema)
+	if !ok {
+		return schema
+	}
+
+	return jsonschema.AnyOf(zero, constrained)
+}
+
+func (m *Module) schemaForFieldZeroValue(field pgs.Field) jsonschema.NonTrivialSchema {
+	m.debug("schemaForFieldZeroValue")
+	switch {
+	case field.Type().IsMap():
+		schema := jsonschema.NewObjectSchema()
+		schema.MaxProperties = jsonschema.Size(0)
+		return schema
+
+	case field.Type().IsRepeated():
+		schema := jsonschema.NewArraySchema()
+		schema.MaxItems = jsonschema.Size(0)
+		return schema
+
+	case m.fieldHasPresence(field):
+		// IGNORE_IF_ZERO_VALUE is a no-op for fields that track presence.
+		return nil
+
+	default:
+		return m.schemaForZeroValue(field.Type())
+	}
+}
+
+func (m *Module) schemaForZeroValue(t typed) jsonschema.NonTrivialSchema {
+	m.debug("schemaForZeroValue")
+	switch {
+	case t.IsEmbed():
+		return nil
+
+	case t.IsEnum() && isNullValue(t.Enum()):
+		return jsonschema.NewNullSchema()
+
+	case t.IsEnum():
+		return m.schemaForEnumValues(t.Enum().Values()[:1])
+
+	case t.ProtoType().IsNumeric():
+		number := jsonschema.NewNumberSchema()
+		number.Const = jsonschema.Number("0")
+
+		switch t.ProtoType() {
+		case pgs.Int64T, pgs.SFixed64, pgs.SInt64, pgs.Fixed64T, pgs.UInt64T:
+			str := jsonschema.NewStringSchema()
+			str.Const = jsonschema.String("0")
+			if m.int64Mode == int64AsString {
+				return str
+			}
+
+			return jsonschema.AnyOf(number, str)
+
+		default:
+			return number
+		}
+
+	case t.ProtoType() == pgs.BoolT:
+		schema := jsonschema.NewBooleanSchema()
+		schema.Const = jsonschema.Boolean(false)
+		return schema
+
+	default:
+		schema := jsonschema.NewStringSchema()
+		schema.Const = jsonschema.String("")
+		return schema
+	}
+}
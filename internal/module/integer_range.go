@@ -0,0 +1,154 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// matchNothing is a pattern that no string matches.
+const matchNothing = `[^\s\S]`
+
+var (
+	bigOne = big.NewInt(1)
+	bigTen = big.NewInt(10)
+)
+
+// integerRange is the range of integers between minimum and maximum, inclusive.
+type integerRange struct {
+	minimum *big.Int
+	maximum *big.Int
+}
+
+// integerRangePattern returns a pattern matching the canonical decimal representations of the integers in any of the
+// ranges.
+func integerRangePattern(ranges ...integerRange) string {
+	var alternatives []string
+	for _, r := range ranges {
+		alternatives = append(alternatives, integerRangeAlternatives(r.minimum, r.maximum)...)
+	}
+
+	if len(alternatives) == 0 {
+		return matchNothing
+	}
+
+	return "^(?:" + strings.Join(alternatives, "|") + ")$"
+}
+
+// integerRangeAlternatives returns patterns which together match the integers between min and max, inclusive.
+func integerRangeAlternatives(minimum, maximum *big.Int) []string {
+	if minimum.Cmp(maximum) > 0 {
+		return nil
+	}
+
+	if minimum.Sign() >= 0 {
+		return naturalRangeAlternatives(minimum, maximum)
+	}
+
+	negativeMaximum := new(big.Int).Neg(minimum)
+	if maximum.Sign() < 0 {
+		return prefixAll("-", naturalRangeAlternatives(new(big.Int).Neg(maximum), negativeMaximum))
+	}
+
+	return append(prefixAll("-", naturalRangeAlternatives(bigOne, negativeMaximum)), naturalRangeAlternatives(new(big.Int), maximum)...)
+}
+
+func prefixAll(prefix string, patterns []string) []string {
+	for i, pattern := range patterns {
+		patterns[i] = prefix + pattern
+	}
+
+	return patterns
+}
+
+// naturalRangeAlternatives splits a range of non-negative integers into ranges that can each be matched by a sequence of
+// fixed digits followed by a digit range and any number of wildcard digits, such as 1[3-9]\d.
+func naturalRangeAlternatives(minimum, maximum *big.Int) []string {
+	stops := []*big.Int{maximum}
+
+	for nines := 1; ; nines++ {
+		stop := fillWithNines(minimum, nines)
+		if stop.Cmp(minimum) < 0 || stop.Cmp(maximum) > 0 {
+			break
+		}
+
+		stops = append(stops, stop)
+	}
+
+	next := new(big.Int).Add(maximum, bigOne)
+	for zeros := 1; ; zeros++ {
+		stop := new(big.Int).Sub(fillWithZeros(next, zeros), bigOne)
+		if stop.Cmp(minimum) <= 0 || stop.Cmp(maximum) > 0 {
+			break
+		}
+
+		stops = append(stops, stop)
+	}
+
+	slices.SortFunc(stops, func(a, b *big.Int) int { return a.Cmp(b) })
+	stops = slices.CompactFunc(stops, func(a, b *big.Int) bool { return a.Cmp(b) == 0 })
+
+	alternatives := make([]string, 0, len(stops))
+	start := minimum
+	for _, stop := range stops {
+		alternatives = append(alternatives, digitRangePattern(start.String(), stop.String()))
+		start = new(big.Int).Add(stop, bigOne)
+	}
+
+	return alternatives
+}
+
+// fillWithNines replaces the last n digits of a number with nines, padding it with leading zeros if necessary.
+func fillWithNines(value *big.Int, n int) *big.Int {
+	digits := []byte(value.String())
+	if n > len(digits) {
+		digits = append([]byte(strings.Repeat("0", n-len(digits))), digits...)
+	}
+
+	for i := len(digits) - n; i < len(digits); i++ {
+		digits[i] = '9'
+	}
+
+	result, _ := new(big.Int).SetString(string(digits), 10)
+	return result
+}
+
+// fillWithZeros replaces the last n digits of a number with zeros.
+func fillWithZeros(value *big.Int, n int) *big.Int {
+	scale := new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+	return new(big.Int).Mul(new(big.Int).Quo(value, scale), scale)
+}
+
+// digitRangePattern matches the numbers between start and stop, which have the same number of digits and differ in
+// at most one digit before a run of digits that can each take any value.
+func digitRangePattern(start, stop string) string {
+	var builder strings.Builder
+	wildcards := 0
+
+	for i := range len(start) {
+		switch {
+		case start[i] == stop[i]:
+			builder.WriteByte(start[i])
+
+		case start[i] == '0' && stop[i] == '9':
+			wildcards++
+
+		default:
+			builder.WriteString("[" + string(start[i]) + "-" + string(stop[i]) + "]")
+		}
+	}
+
+	switch wildcards {
+	case 0:
+	case 1:
+		builder.WriteString(`\d`)
+	default:
+		builder.WriteString(`\d{` + strconv.Itoa(wildcards) + `}`)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,113 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegerRangePattern(t *testing.T) {
+	maxUint64 := new(big.Int).SetUint64(math.MaxUint64)
+
+	testCases := []struct {
+		name    string
+		ranges  []integerRange
+		pattern string
+	}{
+		{name: "zero", ranges: []integerRange{intRange(0, 0)}, pattern: `^(?:0)$`},
+		{name: "one digit", ranges: []integerRange{intRange(0, 9)}, pattern: `^(?:\d)$`},
+		{name: "up to a power of ten", ranges: []integerRange{intRange(0, 10)}, pattern: `^(?:\d|10)$`},
+		{name: "within a decade", ranges: []integerRange{intRange(13, 17)}, pattern: `^(?:1[3-7])$`},
+		{name: "across a power of ten", ranges: []integerRange{intRange(9, 11)}},
+		{name: "across several powers of ten", ranges: []integerRange{intRange(7, 12345)}},
+		{name: "powers of ten", ranges: []integerRange{intRange(10, 1000)}},
+		{name: "around a power of ten", ranges: []integerRange{intRange(99, 101)}},
+		{name: "sign change", ranges: []integerRange{intRange(-1, 1)}, pattern: `^(?:-1|[0-1])$`},
+		{name: "wide sign change", ranges: []integerRange{intRange(-100, 250)}},
+		{name: "negative", ranges: []integerRange{intRange(-1000, -10)}},
+		{name: "negative up to zero", ranges: []integerRange{intRange(-99, 0)}},
+		{name: "int32", ranges: []integerRange{intRange(math.MinInt32, math.MaxInt32)}},
+		{name: "int64", ranges: []integerRange{intRange(math.MinInt64, math.MaxInt64)}},
+		{name: "uint64", ranges: []integerRange{{minimum: new(big.Int), maximum: maxUint64}}},
+		{name: "uint64 top", ranges: []integerRange{{minimum: big.NewInt(math.MaxInt64), maximum: maxUint64}}},
+		{name: "int64 extremes", ranges: []integerRange{intRange(math.MinInt64, math.MinInt64+1), intRange(math.MaxInt64-1, math.MaxInt64)}},
+		{name: "disjoint", ranges: []integerRange{intRange(-20, -5), intRange(5, 20)}},
+		{name: "empty", ranges: []integerRange{intRange(1, 0)}, pattern: matchNothing},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pattern := integerRangePattern(tc.ranges...)
+			if tc.pattern != "" {
+				require.Equal(t, tc.pattern, pattern)
+			}
+
+			re := regexp.MustCompile(pattern)
+			for _, probe := range probes(tc.ranges) {
+				require.Equal(t, inRanges(probe, tc.ranges), re.MatchString(probe.String()), "%s against %s", probe, pattern)
+			}
+
+			for _, noncanonical := range []string{"", "-", "-0", "00", "01", "+1", "1.0", "1e3", " 1"} {
+				require.False(t, re.MatchString(noncanonical), "%q against %s", noncanonical, pattern)
+			}
+		})
+	}
+}
+
+func intRange(minimum, maximum int64) integerRange {
+	return integerRange{minimum: big.NewInt(minimum), maximum: big.NewInt(maximum)}
+}
+
+// probes returns the integers around the bounds of the ranges, zero, and powers of ten of either sign.
+func probes(ranges []integerRange) []*big.Int {
+	var values []*big.Int
+	around := func(value *big.Int) {
+		for delta := int64(-2); delta <= 2; delta++ {
+			values = append(values, new(big.Int).Add(value, big.NewInt(delta)))
+		}
+	}
+
+	around(new(big.Int))
+	for _, r := range ranges {
+		around(r.minimum)
+		around(r.maximum)
+	}
+
+	for power := range 21 {
+		value, _ := new(big.Int).SetString("1"+strings.Repeat("0", power), 10)
+		around(value)
+		around(new(big.Int).Neg(value))
+	}
+
+	return values
+}
+
+func inRanges(value *big.Int, ranges []integerRange) bool {
+	for _, r := range ranges {
+		if value.Cmp(r.minimum) >= 0 && value.Cmp(r.maximum) <= 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestIntegerRangePatternExhaustive(t *testing.T) {
+	// A spread of small ranges is checked against every integer around them.
+	for minimum := int64(-120); minimum <= 120; minimum += 17 {
+		for maximum := minimum; maximum <= 1100; maximum += 29 {
+			re := regexp.MustCompile(integerRangePattern(intRange(minimum, maximum)))
+			for value := int64(-150); value <= 1150; value++ {
+				require.Equal(t, value >= minimum && value <= maximum, re.MatchString(strconv.FormatInt(value, 10)), "%d in [%d, %d]", value, minimum, maximum)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"encoding/json"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	pgv "github.com/envoyproxy/protoc-gen-validate/validate"
+	pgs "github.com/lyft/protoc-gen-star/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// legacyFieldRules reads the rules from the protoc-gen-validate extension, which was superseded by protovalidate.
+// The two sets of rules are mostly identical, so they are converted by way of their JSON encodings.
+func (m *Module) legacyFieldRules(field pgs.Field) *validate.FieldRules {
+	m.debug("legacyFieldRules")
+	legacy := &pgv.FieldRules{}
+	ok, err := field.Extension(pgv.E_Rules, legacy)
+	m.CheckErr(err, "unable to read legacy validation rules from field")
+	if !ok {
+		return nil
+	}
+
+	data, err := protojson.MarshalOptions{UseProtoNames: true, UseEnumNumbers: true}.Marshal(legacy)
+	m.CheckErr(err, "failed to marshal legacy validation rules to JSON")
+
+	var rules map[string]any
+	err = json.Unmarshal(data, &rules)
+	m.CheckErr(err, "failed to unmarshal legacy validation rules from JSON")
+
+	upgradeLegacyFieldRules(rules)
+
+	data, err = json.Marshal(rules)
+	m.CheckErr(err, "failed to marshal upgraded validation rules to JSON")
+
+	upgraded := &validate.FieldRules{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, upgraded)
+	m.CheckErr(err, "failed to unmarshal upgraded validation rules from JSON")
+
+	return upgraded
+}
+
+// upgradeLegacyFieldRules moves the legacy rules that have changed shape to where protovalidate expects them.
+// Rules that protovalidate doesn't support, such as no_sparse, are discarded when unmarshalling.
+func upgradeLegacyFieldRules(rules map[string]any) {
+	for name, value := range rules {
+		typeRules, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if typeRules["required"] == true {
+			delete(typeRules, "required")
+			rules["required"] = true
+		}
+
+		if typeRules["ignore_empty"] == true {
+			rules["ignore"] = validate.Ignore_IGNORE_IF_ZERO_VALUE.String()
+		}
+
+		if typeRules["skip"] == true {
+			rules["ignore"] = validate.Ignore_IGNORE_ALWAYS.String()
+		}
+
+		for _, nested := range []string{"items", "keys", "values"} {
+			if nestedRules, ok := typeRules[nested].(map[string]any); ok {
+				upgradeLegacyFieldRules(nestedRules)
+			}
+		}
+
+		if name == "message" {
+			delete(rules, name)
+		}
+	}
+}
+
+// legacyOneOfRequired reads the protoc-gen-validate extension that requires a oneof to be set.
+func (m *Module) legacyOneOfRequired(oneOf pgs.OneOf) bool {
+	m.debug("legacyOneOfRequired")
+	var required bool
+	_, err := oneOf.Extension(pgv.E_Required, &required)
+	m.CheckErr(err, "unable to read legacy oneOf option")
+	return required
+}
+
+// legacyMessageDisabled reads the protoc-gen-validate extensions that turn off validation for a message.
+func (m *Module) legacyMessageDisabled(message pgs.Message) bool {
+	m.debug("legacyMessageDisabled")
+	var disabled, ignored bool
+	_, err := message.Extension(pgv.E_Disabled, &disabled)
+	m.CheckErr(err, "unable to read legacy disabled option")
+	_, err = message.Extension(pgv.E_Ignored, &ignored)
+	m.CheckErr(err, "unable to read legacy ignored option")
+	return disabled || ignored
+}
@@ -0,0 +1,36 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyRules(t *testing.T) {
+	res := render(t, "")
+
+	doc := document(t, res, "testproto/LegacyRulesTest.schema.json")
+	object := lookup(t, doc, "allOf", "0")
+	properties := lookup(t, object, "properties")
+
+	require.Equal(t, []any{"embeddedField", "tags"}, lookup(t, object, "required"))
+	require.Equal(t, []any{
+		map[string]any{"type": "string", "const": ""},
+		map[string]any{"type": "string", "minLength": float64(1), "maxLength": float64(5)},
+	}, lookup(t, properties, "stringField", "anyOf"))
+	require.Equal(t, float64(10), lookup(t, properties, "uint32Field", "exclusiveMinimum"))
+	require.Equal(t, float64(1), lookup(t, properties, "tags", "minItems"))
+	require.Equal(t, "email", lookup(t, properties, "tags", "items", "format"))
+
+	// The required oneof rule leaves out the alternative of setting neither field.
+	require.Equal(t, []any{
+		map[string]any{"type": "object", "required": []any{"first"}},
+		map[string]any{"type": "object", "required": []any{"second"}},
+	}, lookup(t, doc, "allOf", "1", "oneOf"))
+
+	disabled := document(t, res, "testproto/LegacyDisabledRulesTest.schema.json")
+	require.Equal(t, map[string]any{"type": "string"}, lookup(t, disabled, "properties", "stringField"))
+}
@@ -0,0 +1,167 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	pgs "github.com/lyft/protoc-gen-star/v2"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/common"
+)
+
+// Log levels accepted by the log_level parameter.
+var logLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// scope is an entity that the generator is working on, which is attached to the messages that are logged.
+type scope struct {
+	key   string
+	value string
+	// position is where the entity is defined in the proto source, such as foo/bar.proto:12:3, if it is known.
+	position string
+}
+
+// newLogger returns a logger that writes to stderr, which protoc passes through to the user. The time is left out,
+// since runs are short and the output is easier to compare between them without it.
+func newLogger(level slog.Leveler) *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return attr
+		},
+	}))
+}
+
+// defaultLogLevel is debug if the debug environment variable is set, so that it keeps working as before, and
+// otherwise warn.
+func defaultLogLevel() string {
+	if _, ok := os.LookupEnv(common.DebugEnv); ok {
+		return "debug"
+	}
+
+	return "warn"
+}
+
+func (m *Module) parseLogLevel(value string) slog.Level {
+	level, ok := logLevels[strings.ToLower(value)]
+	if !ok {
+		m.Failf("invalid value %q for log_level parameter (expected one of %q)", value, []string{"debug", "info", "warn", "error"})
+	}
+
+	return level
+}
+
+// enter starts working on an entity, such as a file or a field, until the matching call to leave. The entity is
+// attached to logged messages, and to the error if generation fails.
+func (m *Module) enter(key, value string) {
+	m.Push(fmt.Sprintf("%s:%s", key, value))
+	m.scopes = append(m.scopes, scope{key: key, value: value})
+}
+
+// enterEntity starts working on an entity that is defined in the proto source, whose position is added to the error if
+// generation fails.
+func (m *Module) enterEntity(key, value string, entity pgs.Entity) {
+	m.enter(key, value)
+	m.scopes[len(m.scopes)-1].position = sourcePosition(entity)
+}
+
+func (m *Module) leave() {
+	m.scopes = m.scopes[:len(m.scopes)-1]
+	m.Pop()
+}
+
+// sourcePosition returns the position of an entity in the proto source. protoc only includes source code info for the
+// files being generated, so the position of an entity from a dependency is just its file.
+func sourcePosition(entity pgs.Entity) string {
+	file := entity.File().Name().String()
+	info := entity.SourceCodeInfo()
+	if info == nil || len(info.Location().GetSpan()) < 2 {
+		return file
+	}
+
+	span := info.Location().GetSpan()
+	return fmt.Sprintf("%s:%d:%d", file, span[0]+1, span[1]+1)
+}
+
+// position returns the position in the proto source of the innermost entity being worked on, if there is one.
+func (m *Module) position() string {
+	for _, s := range slices.Backward(m.scopes) {
+		if s.position != "" {
+			return s.position
+		}
+	}
+
+	return ""
+}
+
+// Fail adds the position of the entity being worked on to the error, so that it can be found in the proto source.
+func (m *Module) Fail(v ...any) {
+	m.Failf("%s", fmt.Sprint(v...))
+}
+
+// Failf adds the position of the entity being worked on to the error, so that it can be found in the proto source.
+func (m *Module) Failf(format string, args ...any) {
+	if position := m.position(); position != "" {
+		format = strings.ReplaceAll(position, "%", "%%") + ": " + format
+	}
+
+	m.ModuleBase.Failf(format, args...)
+}
+
+// CheckErr adds the position of the entity being worked on to the error, so that it can be found in the proto source.
+func (m *Module) CheckErr(err error, v ...any) {
+	if position := m.position(); err != nil && position != "" {
+		v = append([]any{position + ": "}, v...)
+	}
+
+	m.ModuleBase.CheckErr(err, v...)
+}
+
+func (m *Module) debug(msg string, args ...any) {
+	m.log(slog.LevelDebug, msg, args...)
+}
+
+func (m *Module) warn(msg string, args ...any) {
+	m.log(slog.LevelWarn, msg, args...)
+}
+
+// log logs a message with the entities that the generator is working on. Only the innermost entity of each kind is
+// included, such as the message nested in another message that the field being logged belongs to.
+func (m *Module) log(level slog.Level, msg string, args ...any) {
+	ctx := context.Background()
+	if !m.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var keys []string
+	values := make(map[string]string)
+	for _, s := range m.scopes {
+		if _, ok := values[s.key]; !ok {
+			keys = append(keys, s.key)
+		}
+
+		values[s.key] = s.value
+	}
+
+	attrs := make([]any, 0, len(keys)+len(args))
+	for _, key := range keys {
+		attrs = append(attrs, slog.String(key, values[key]))
+	}
+
+	m.logger.Log(ctx, level, msg, slices.Concat(attrs, args)...)
+}
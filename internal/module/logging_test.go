@@ -0,0 +1,51 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevel(t *testing.T) {
+	warning := `level=WARN msg="rule can't be expressed in JSON Schema" file=testproto/testproto.proto message=testproto.StringRulesTest field=bytes_field rule=string.min_bytes`
+
+	testCases := []struct {
+		parameter string
+		debug     bool
+		warn      bool
+	}{
+		{parameter: "", warn: true},
+		{parameter: "log_level=debug", debug: true, warn: true},
+		{parameter: "log_level=info", warn: true},
+		{parameter: "log_level=ERROR"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			output, err := renderInSubprocess(t, tc.parameter)
+			require.NoError(t, err, output)
+
+			if tc.warn {
+				require.Contains(t, output, warning)
+			} else {
+				require.NotContains(t, output, "level=WARN")
+			}
+
+			if tc.debug {
+				require.Contains(t, output, "level=DEBUG msg=schemaForField file=testproto/testproto.proto message=testproto.FieldNamesTest field=snake_case_field")
+			} else {
+				require.NotContains(t, output, "level=DEBUG")
+			}
+		})
+	}
+
+	require.Contains(t, renderFailure(t, "log_level=verbose"), `invalid value "verbose" for log_level parameter`)
+}
+
+func TestErrorPosition(t *testing.T) {
+	output := renderFailure(t, "draft=draft-04")
+	require.Regexp(t, `\[field:json_field\] testproto/testproto\.proto:\d+:3: content_media_type option requires draft draft-07 or later`, output)
+}
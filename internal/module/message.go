@@ -4,118 +4,181 @@
 package module
 
 import (
-	"fmt"
-
 	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	pgs "github.com/lyft/protoc-gen-star/v2"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
 )
 
+// messageRulesDisabledNumber is the field number of the disabled flag in older versions of protovalidate.
+const messageRulesDisabledNumber protowire.Number = 1
+
 func (m *Module) defineMessage(message pgs.Message) jsonschema.NonTrivialSchema {
 	m.pushMessage(message)
-	m.Debug("defineMessage")
-
-	schema := jsonschema.NewObjectSchema()
-	schema.AdditionalProperties = jsonschema.False
-	schemas := []jsonschema.NonTrivialSchema{schema}
-
-	for _, field := range message.Fields() {
-		name := m.propertyName(field)
-		valueSchema, required := m.schemaForField(field)
-		schema.Properties[name] = valueSchema
-		if required {
-			schema.Required = append(schema.Required, name)
+	m.debug("defineMessage")
+
+	schema, constraints := m.schemaForMessageFields(message)
+	result := jsonschema.AllOf(append([]jsonschema.NonTrivialSchema{schema}, constraints...)...)
+	m.popMessage(message, result)
+	return result
+}
+
+// schemaForMessageFields returns an object schema with a property for each of the message's fields, along with any
+// constraints that span multiple fields.
+func (m *Module) schemaForMessageFields(message pgs.Message) (*jsonschema.ObjectSchema, []jsonschema.NonTrivialSchema) {
+	m.debug("schemaForMessageFields")
+	schema := jsonschema.NewClosedObjectSchema()
+	var constraints []jsonschema.NonTrivialSchema
+
+	disabled := m.messageRulesDisabled(message)
+	if !disabled {
+		m.checkMessageRules(message)
+	}
+
+	fields := message.Fields()
+	for _, extension := range message.Extensions() {
+		fields = append(fields, extension)
+	}
+
+	for _, field := range fields {
+		if m.fieldOptions(field).GetHide() {
+			continue
 		}
+
+		valueSchema, required := m.schemaForField(field, disabled)
+		constraints = append(constraints, m.addProperty(schema, field, m.propertyNames(field), valueSchema, required)...)
 	}
 
-	for _, oneOf := range message.OneOfs() {
-		oneOfSchema := m.schemaForOneOf(oneOf)
+	options := m.messageOptions(message)
+	schema.Title = options.GetTitle()
+	schema.Description = options.GetDescription()
+	schema.AddExamples(m.parseExamples(options.GetExamples())...)
+	m.applyPropertyOrder(schema)
+	m.applyOverride(message, schema)
+
+	for _, oneOf := range message.RealOneOfs() {
+		oneOfSchema := m.schemaForOneOf(oneOf, disabled)
 		if oneOfSchema != nil {
-			schemas = append(schemas, oneOfSchema)
+			constraints = append(constraints, oneOfSchema)
 		}
 	}
 
-	result := jsonschema.AllOf(schemas...)
-	m.popMessage(message, result)
-	return result
-}
-
-func (m *Module) propertyName(field pgs.Field) string {
-	return field.Descriptor().GetJsonName()
+	return schema, constraints
 }
 
-func (m *Module) schemaForField(field pgs.Field) (jsonschema.Schema, bool) {
-	m.Push(fmt.Sprintf("field:%s", field.Name()))
-	defer m.Pop()
-	m.Debug("schemaForField")
+func (m *Module) schemaForField(field pgs.Field, disabled bool) (jsonschema.Schema, bool) {
+	m.enterEntity("field", field.Name().String(), field)
+	defer m.leave()
+	m.debug("schemaForField")
 
 	rules := &validate.FieldRules{}
-	_, err := field.Extension(validate.E_Field, rules)
+	ok, err := field.Extension(validate.E_Field, rules)
 	m.CheckErr(err, "unable to read validation rules from field")
+	if !ok {
+		if legacy := m.legacyFieldRules(field); legacy != nil {
+			rules = legacy
+		}
+	}
 
-	required := rules.GetRequired()
-	if rules.GetIgnore() == validate.Ignore_IGNORE_IF_ZERO_VALUE {
-		required = false
+	if disabled {
+		rules = nil
 	}
 
-	if field.HasOptionalKeyword() {
-		required = false
+	if rules.GetIgnore() == validate.Ignore_IGNORE_ALWAYS {
+		rules = nil
 	}
 
+	m.checkFieldRules(field, rules, "")
+	required := m.fieldRequired(field, rules)
+
+	options := m.fieldOptions(field)
 	var schema jsonschema.Schema
 	switch {
+	case options.GetStructSchema() != "":
+		schema = m.schemaForStructSchemaOption(field, options)
 	case field.Type().IsEmbed():
 		schema = m.schemaForEmbed(field.Type().Embed(), rules)
 	case field.Type().IsEnum():
 		schema = m.schemaForEnum(field.Type().Enum(), rules.GetEnum())
 	case field.Type().IsMap():
-		schema = m.schemaForMap(field.Type().Element(), rules.GetMap())
+		schema = m.schemaForMap(field.Type().Key(), field.Type().Element(), rules.GetMap())
 	case field.Type().IsRepeated():
 		schema = m.schemaForRepeated(field.Type().Element(), rules.GetRepeated())
 	default:
 		schema = m.schemaForScalar(field.Type().ProtoType(), rules)
 	}
 
-	return schema, required && !field.InOneOf()
+	schema = m.applyFormatOption(field, options, schema)
+	schema = m.applyContentOptions(field, options, schema)
+	schema = m.applySchemaOption(options, schema)
+	schema = m.applyIgnore(m.schemaForFieldZeroValue(field), rules, schema)
+	schema = m.applyExamples(field.Type(), rules, schema)
+	schema = jsonschema.WithExamples(schema, m.parseExamples(options.GetExamples())...)
+	schema = jsonschema.WithTitle(schema, options.GetTitle())
+	schema = jsonschema.WithDescription(schema, options.GetDescription())
+	schema = m.applyAccessOptions(options, schema)
+	schema = m.applyDefault(field, options, schema)
+
+	// protojson treats null as unset, which is only acceptable for wrappers if the field isn't required.
+	wrapper := field.Type().IsEmbed() && isWrapper(field.Type().Embed()) && !required
+	optional := m.fieldHasOptionalKeyword(field) && m.optionalMode == optionalNullable && !required
+	if wrapper || optional || (m.allowNullValues && !required) {
+		schema = jsonschema.Nullable(schema)
+	}
+
+	return schema, required && !field.InRealOneOf()
 }
 
 func (m *Module) schemaForEmbed(embed pgs.Message, rules *validate.FieldRules) jsonschema.Schema {
-	m.Debug("schemaForEmbed")
+	m.debug("schemaForEmbed")
 	if embed.IsWellKnown() {
 		return m.schemaForWellKnownType(embed.WellKnownType(), rules)
 	}
 
+	if isFieldMask(embed) {
+		return m.ref(wellKnownTypeFieldMask, m.defineFieldMask)
+	}
+
+	if schema := m.schemaForGoogleType(embed); schema != nil {
+		return schema
+	}
+
 	return m.schemaForMessage(embed)
 }
 
 func (m *Module) schemaForMessage(message pgs.Message) jsonschema.Schema {
-	m.Debug("schemaForMessage")
+	m.debug("schemaForMessage")
 	return m.messageRef(message)
 }
 
-func (m *Module) schemaForOneOf(oneOf pgs.OneOf) jsonschema.NonTrivialSchema {
-	m.Debug("schemaForOneOf")
-	rules := validate.OneofRules{}
-	_, err := oneOf.Extension(validate.E_Oneof, &rules)
-	m.CheckErr(err, "unable to read oneOf option")
-
-	if rules.Required == nil || !*rules.Required {
-		return nil
+// messageRulesDisabled reports whether the message opts out of validation. The disabled flag was removed from
+// protovalidate's message rules, but older definitions that still set it are read back from the unknown fields.
+func (m *Module) messageRulesDisabled(message pgs.Message) bool {
+	m.debug("messageRulesDisabled")
+	rules := &validate.MessageRules{}
+	_, err := message.Extension(validate.E_Message, rules)
+	m.CheckErr(err, "unable to read validation rules from message")
+
+	for _, value := range m.unknownVarints(rules.ProtoReflect(), messageRulesDisabledNumber) {
+		if value != 0 {
+			return true
+		}
 	}
 
-	schemas := make([]jsonschema.NonTrivialSchema, len(oneOf.Fields()))
-	for i, field := range oneOf.Fields() {
-		schema := jsonschema.NewObjectSchema()
-		schema.Required = []string{m.propertyName(field)}
-		schemas[i] = schema
-	}
+	return m.legacyMessageDisabled(message)
+}
 
-	return jsonschema.OneOf(schemas...)
+// applyPropertyOrder records the order of the properties in an x-propertyOrder extension, for form generators that
+// don't rely on the order of the keys in a JSON object.
+func (m *Module) applyPropertyOrder(schema *jsonschema.ObjectSchema) {
+	if m.propertyOrder {
+		schema.SetExtension("x-propertyOrder", schema.Properties.Names())
+	}
 }
 
-func (m *Module) messageRef(message pgs.Message) jsonschema.Schema {
-	m.Debug("messageRef")
+func (m *Module) messageRef(message pgs.Message) *jsonschema.GenericSchema {
+	m.debug("messageRef")
 	return m.ref(message, func() jsonschema.Schema {
 		return m.defineMessage(message)
 	})
@@ -0,0 +1,65 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowNullValues(t *testing.T) {
+	nullable := func(schema any) any {
+		return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+	}
+
+	doc := document(t, render(t, "allow_null_values=true"), "testproto/RequiredModesTest.schema.json")
+	properties := lookup(t, doc, "properties")
+
+	require.Equal(t, nullable(map[string]any{"type": "string"}), lookup(t, properties, "plainField"))
+	require.Equal(t, nullable(map[string]any{"$ref": "#/definitions/google.protobuf.Empty"}), lookup(t, properties, "messageField"))
+	require.Equal(t, nullable(map[string]any{"type": "array", "items": map[string]any{"type": "string"}}), lookup(t, properties, "repeatedField"))
+
+	// protojson treats null as unset, which a required property can't be.
+	require.Equal(t, map[string]any{"type": "string"}, lookup(t, properties, "ruleField"))
+}
+
+func TestPropertyOrder(t *testing.T) {
+	declared := []string{"ruleField", "plainField", "optionalField", "repeatedField", "messageField", "behaviorField", "first", "optionalBehaviorField"}
+
+	// Properties are written in the order that the fields are declared in.
+	raw := content(t, render(t, ""), "testproto/RequiredModesTest.schema.json")
+	offset := 0
+	for _, name := range declared {
+		index := strings.Index(raw[offset:], strconv.Quote(name)+":")
+		require.GreaterOrEqual(t, index, 0, "%s out of order", name)
+		offset += index
+	}
+
+	doc := document(t, render(t, ""), "testproto/RequiredModesTest.schema.json")
+	require.NotContains(t, doc, "x-propertyOrder")
+
+	doc = document(t, render(t, "property_order=true"), "testproto/RequiredModesTest.schema.json")
+	order := make([]any, len(declared))
+	for i, name := range declared {
+		order[i] = name
+	}
+
+	require.Equal(t, order, lookup(t, doc, "x-propertyOrder"))
+}
+
+func TestHideOption(t *testing.T) {
+	for _, parameter := range []string{"", "required=all"} {
+		t.Run(parameter, func(t *testing.T) {
+			raw := content(t, render(t, parameter), "testproto/HiddenFieldTest.schema.json")
+
+			// Hidden fields are left out entirely, including from oneofs and the required properties.
+			require.NotContains(t, raw, "internalState")
+			require.NotContains(t, raw, "hiddenChoice")
+			require.Contains(t, raw, "visibleChoice")
+		})
+	}
+}
@@ -4,59 +4,255 @@
 package module
 
 import (
-	"encoding/json"
-	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
+	"text/template"
 
 	pgs "github.com/lyft/protoc-gen-star/v2"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/pluginpb"
 
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonpatch"
 	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
 )
 
 type Module struct {
 	*pgs.ModuleBase
-	nestedUnderMessage pgs.Message
-	definitions        map[string]jsonschema.Schema
+	nestedUnderMessage  namedEntity
+	definitions         map[string]jsonschema.Schema
+	requiredMode        requiredMode
+	optionalMode        optionalMode
+	draft               jsonschema.Draft
+	defaultDraft        jsonschema.Draft
+	int64Mode           int64Mode
+	enumMode            enumMode
+	enumPrefixMode      enumPrefixMode
+	openEnums           bool
+	enumDescriptions    bool
+	anyMessages         []pgs.Message
+	descriptors         *protoregistry.Files
+	nonFiniteFloats     bool
+	base64Mode          base64Mode
+	fieldNamesMode      fieldNamesMode
+	oneOfMode           oneOfMode
+	allowNullValues     bool
+	idTemplate          string
+	filenameTemplate    string
+	extension           string
+	filter              messageFilter
+	topLevelOnly        bool
+	onlyAnnotated       bool
+	groupMode           groupMode
+	root                string
+	indent              int
+	propertyOrder       bool
+	overrides           map[string]messageOverride
+	patches             []jsonpatch.Operation
+	outputTemplate      *template.Template
+	strict              bool
+	warningsReport      string
+	unsupportedRules    map[unsupportedRule]struct{}
+	logger              *slog.Logger
+	logLevel            slog.LevelVar
+	scopes              []scope
+	parameter           string
+	params              parameters
+	omitTitles          bool
+	formatMode          formatMode
+	formatAssertion     bool
+	baseURL             string
+	idBase              string
+	anchors             bool
+	dynamicRefs         bool
+	recursive           map[string]bool
+	definitionNamesMode definitionNamesMode
+	shortNames          map[string]string
+	collidingFilenames  map[string]bool
+	filenames           map[string]struct{}
+	provenanceMode      provenanceMode
+	compilerVersion     *pluginpb.Version
 }
 
-func New() pgs.Module {
-	return &Module{ModuleBase: &pgs.ModuleBase{}}
+// New returns the module, given the parameter string of the code generator request and the version of protoc that sent
+// it, if it is known. protoc-gen-star also parses the parameter string, but it doesn't support escaping or repeated
+// parameters, so the module parses it again itself.
+func New(parameter string, compilerVersion *pluginpb.Version) pgs.Module {
+	m := &Module{ModuleBase: &pgs.ModuleBase{}, parameter: parameter, compilerVersion: compilerVersion}
+	m.logger = newLogger(&m.logLevel)
+	return m
 }
 
 func (*Module) Name() string {
 	return "jsonschema"
 }
 
-func (m *Module) Execute(targets map[string]pgs.File, _ map[string]pgs.Package) []pgs.Artifact {
-	baseURL := m.Parameters().StrDefault("baseurl", "https://protoc-gen-jsonschema.cerbos.dev/")
-	if !strings.HasSuffix(baseURL, "/") {
-		baseURL += "/"
+func (m *Module) Execute(targets map[string]pgs.File, packages map[string]pgs.Package) []pgs.Artifact {
+	params, err := parseParameters(m.parameter)
+	m.CheckErr(err, "invalid plugin parameters")
+	m.params = params
+
+	if config := m.params.str("config", ""); config != "" {
+		m.loadConfig(config)
 	}
 
-	for _, file := range targets {
-		m.Push(fmt.Sprintf("file:%s", file.Name()))
+	m.logLevel.Set(m.parseLogLevel(m.params.str("log_level", defaultLogLevel())))
+
+	m.baseURL = m.params.str("baseurl", "https://protoc-gen-jsonschema.cerbos.dev/")
+	if !strings.HasSuffix(m.baseURL, "/") {
+		m.baseURL += "/"
+	}
 
-		for _, message := range file.AllMessages() {
-			filename := m.filename(message)
+	m.idTemplate = m.params.str("id_template", m.baseURL+placeholderFilename)
+	m.filenameTemplate = m.params.str("filename_template", "")
+	m.extension = m.parseExtension(m.params.str("extension", defaultExtension))
+	m.indent = m.parseIndent(m.params.str("indent", strconv.Itoa(defaultIndent)))
+	m.filter = m.parseMessageFilter(m.params.str("include", ""), m.params.str("exclude", ""))
+	m.groupMode = m.parseGroupMode(m.params.str("group", string(groupByMessage)))
+	m.root = m.params.str("root", "")
+	if outputTemplate := m.params.str("output_template", ""); outputTemplate != "" {
+		m.outputTemplate = m.parseOutputTemplate(outputTemplate)
+	}
 
-			schema := m.defineMessage(message)
-			schema.TopLevel(baseURL + filename)
+	m.requiredMode = m.parseRequiredMode(m.params.str("required", string(requiredFromRules)))
+	m.optionalMode = m.parseOptionalMode(m.params.str("optional", string(optionalNotRequired)))
+	m.defaultDraft = m.parseDraft("draft parameter", m.params.str("draft", string(jsonschema.Draft07)))
+	m.draft = m.defaultDraft
+	m.int64Mode = m.parseInt64Mode(m.params.str("int64", string(int64AsNumberOrString)))
+	m.enumMode = m.parseEnumMode(m.params.str("enum", string(enumAsName)))
+	m.enumPrefixMode = m.parseEnumPrefixMode(m.params.str("enum_prefix", string(enumPrefixKeep)))
+	m.base64Mode = m.parseBase64Mode(m.params.str("base64", string(base64StandardOrURLSafe)))
+	m.fieldNamesMode = m.parseFieldNamesMode(m.params.str("field_names", string(fieldNamesJSON)))
+	m.definitionNamesMode = m.parseDefinitionNamesMode(m.params.str("definition_names", string(definitionNamesFull)))
+	m.formatMode = m.parseFormatMode(m.params.str("formats", string(formatAsKeyword)))
+	m.formatAssertion = m.params.flag("format_assertion")
+	m.anchors = m.params.flag("anchors")
+	m.dynamicRefs = m.params.flag("dynamic_refs")
+	m.checkDraft()
+	m.oneOfMode = m.parseOneOfMode(m.params.str("oneof", string(oneOfStrict)))
 
-			content, err := json.MarshalIndent(schema, "", "  ")
-			m.CheckErr(err, "failed to marshal JSON schema")
+	m.openEnums = m.params.flag("open_enums")
+	m.enumDescriptions = m.params.flag("enum_descriptions")
+	m.nonFiniteFloats = m.params.flag("non_finite_floats")
+	m.allowNullValues = m.params.flag("allow_null_values")
+	m.propertyOrder = m.params.flag("property_order")
+	m.topLevelOnly = m.params.flag("top_level_only")
+	m.onlyAnnotated = m.params.flag("only_annotated")
+	m.strict = m.params.flag("strict")
+	m.omitTitles = m.params.flag("omit_titles")
+	m.warningsReport = m.params.str("warnings_report", "")
+	m.provenanceMode = m.parseProvenanceMode(m.params.str("provenance", string(provenanceNone)))
 
-			m.AddGeneratorFile(filename, string(content)+"\n")
+	files := allFiles(targets)
+	m.descriptors = m.buildDescriptors(files)
+	if m.definitionNamesMode == definitionNamesShort {
+		m.shortNames = uniqueShortNames(files)
+	}
+
+	if m.params.flag("expand_any") {
+		m.anyMessages = allMessages(files)
+	}
+
+	m.filenames = make(map[string]struct{})
+	m.collidingFilenames = m.findCollidingFilenames(targets)
+
+	if m.groupMode == groupByPackage {
+		for _, pkg := range packages {
+			m.addPackageDocument(pkg, targets)
 		}
+	}
 
-		m.Pop()
+	for _, file := range targets {
+		if m.fileGroupMode(file) != groupByPackage {
+			m.addFileDocuments(file)
+		}
 	}
 
+	m.addMetaSchema()
+	m.addWarningsReport()
 	return m.Artifacts()
 }
 
-func (*Module) filename(message pgs.Message) string {
-	name := message.FullyQualifiedName()
-	name = strings.TrimPrefix(name, ".")
-	name = strings.ReplaceAll(name, ".", "/")
-	return name + ".schema.json"
+// addFileDocuments adds the documents for the messages declared in a file, according to the group parameter or the
+// file's options.
+func (m *Module) addFileDocuments(file pgs.File) {
+	m.enter("file", file.Name().String())
+	defer m.leave()
+
+	m.useFileOptions(m.fileOptions(file))
+	messages := m.selectMessages(file)
+	if m.onlyAnnotated && len(messages) == 0 {
+		return
+	}
+
+	switch m.fileGroupMode(file) {
+	case groupByFile:
+		m.addFileDocument(file, messages)
+
+	default:
+		for _, message := range messages {
+			m.addMessageDocument(message)
+		}
+	}
+}
+
+// allFiles returns the target files and their dependencies, which together make up every file in the request.
+func allFiles(targets map[string]pgs.File) []pgs.File {
+	files := make(map[string]pgs.File)
+	for _, target := range targets {
+		files[target.Name().String()] = target
+		for _, file := range target.TransitiveImports() {
+			files[file.Name().String()] = file
+		}
+	}
+
+	names := slices.Sorted(maps.Keys(files))
+	result := make([]pgs.File, len(names))
+	for i, name := range names {
+		result[i] = files[name]
+	}
+
+	return result
+}
+
+// allMessages returns the messages declared in the files, sorted by name.
+func allMessages(files []pgs.File) []pgs.Message {
+	var messages []pgs.Message
+	for _, file := range files {
+		messages = append(messages, file.AllMessages()...)
+	}
+
+	slices.SortFunc(messages, func(a, b pgs.Message) int {
+		return strings.Compare(a.FullyQualifiedName(), b.FullyQualifiedName())
+	})
+
+	return messages
+}
+
+// parseDraft parses a draft, which is described by where it was given, such as "draft parameter".
+func (m *Module) parseDraft(source, value string) jsonschema.Draft {
+	draft := jsonschema.Draft(value)
+	if !slices.Contains(jsonschema.Drafts, draft) {
+		m.Failf("invalid value %q for %s (expected one of %q)", value, source, jsonschema.Drafts)
+	}
+
+	return draft
+}
+
+// checkDraft fails if parameters are set that the draft of the documents being generated doesn't support. It is
+// checked again whenever the options of a file change the draft.
+func (m *Module) checkDraft() {
+	if m.formatAssertion && m.draft != jsonschema.Draft202012 {
+		m.Failf("format_assertion parameter requires draft %s, since earlier drafts don't define the format-assertion vocabulary", jsonschema.Draft202012)
+	}
+
+	if m.anchors && !m.draft.Anchors() {
+		m.Failf("anchors parameter requires draft %s or %s, since earlier drafts don't define $anchor", jsonschema.Draft201909, jsonschema.Draft202012)
+	}
+
+	if m.dynamicRefs && m.draft != jsonschema.Draft202012 {
+		m.Failf("dynamic_refs parameter requires draft %s, since earlier drafts don't define $dynamicRef", jsonschema.Draft202012)
+	}
 }
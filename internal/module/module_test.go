@@ -5,11 +5,18 @@ package module_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 
 	pgs "github.com/lyft/protoc-gen-star/v2"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
 
 	"github.com/cerbos/protoc-gen-jsonschema/internal/common"
 	"github.com/cerbos/protoc-gen-jsonschema/internal/module"
@@ -19,12 +26,167 @@ import (
 const requestName = "code_generator_request.pb.bin"
 
 func TestModule(t *testing.T) {
-	reqFile, err := os.Open(test.PathToDir(t, requestName))
+	render(t, "") // The parameter of the request is protoc-gen-debug's, not the module's.
+}
+
+func TestModuleNameCollisions(t *testing.T) {
+	// testproto and testproto.other both declare a NameCollisionTest message.
+	filenames := filenames(t, render(t, "filename_template={message}.schema.json"))
+	require.Contains(t, filenames, "testproto.NameCollisionTest.schema.json")
+	require.Contains(t, filenames, "testproto.other.NameCollisionTest.schema.json")
+	require.NotContains(t, filenames, "NameCollisionTest.schema.json")
+	require.Contains(t, filenames, "RecursiveTest.schema.json")
+}
+
+func TestModuleFileOptions(t *testing.T) {
+	// testproto.fileoptions sets its own id_base and draft.
+	res := render(t, "")
+	require.Empty(t, res.GetError())
+
+	var document map[string]any
+	for _, file := range res.GetFile() {
+		if file.GetName() == "testproto/fileoptions/FileOptionsTest.schema.json" {
+			require.NoError(t, json.Unmarshal([]byte(file.GetContent()), &document))
+		}
+	}
+
+	require.Equal(t, "https://example.com/schemas/testproto/fileoptions/FileOptionsTest.schema.json", document["$id"])
+	require.Equal(t, "https://json-schema.org/draft/2020-12/schema", document["$schema"])
+}
+
+func TestModuleOnlyAnnotated(t *testing.T) {
+	// GenerateOptionTest is the only message that sets the generate option.
+	res := render(t, "only_annotated=true")
+	require.Empty(t, res.GetError())
+	require.Len(t, res.GetFile(), 1)
+	require.Equal(t, "testproto/GenerateOptionTest.schema.json", res.GetFile()[0].GetName())
+}
+
+func render(t *testing.T, parameter string) *pluginpb.CodeGeneratorResponse {
+	t.Helper()
+	return renderEdited(t, parameter, nil)
+}
+
+// renderEdited renders the test protos after editing the request, for options that can't be set in the test protos
+// without changing what every other test renders.
+func renderEdited(t *testing.T, parameter string, edit func(*pluginpb.CodeGeneratorRequest)) *pluginpb.CodeGeneratorResponse {
+	t.Helper()
+
+	reqBytes, err := os.ReadFile(test.PathToDir(t, requestName))
 	require.NoError(t, err)
+
+	if edit != nil {
+		req := &pluginpb.CodeGeneratorRequest{}
+		require.NoError(t, proto.Unmarshal(reqBytes, req))
+		edit(req)
+		reqBytes, err = proto.Marshal(req)
+		require.NoError(t, err)
+	}
+
 	resBytes := &bytes.Buffer{}
 	pgs.Init(
 		pgs.DebugEnv(common.DebugEnv),
-		pgs.ProtocInput(reqFile),
+		pgs.ProtocInput(bytes.NewReader(reqBytes)),
 		pgs.ProtocOutput(resBytes),
-	).RegisterModule(module.New()).Render()
+	).RegisterModule(module.New(parameter, nil)).Render()
+
+	res := &pluginpb.CodeGeneratorResponse{}
+	require.NoError(t, proto.Unmarshal(resBytes.Bytes(), res))
+	return res
+}
+
+// subprocessParameterEnv passes the parameter to the subprocesses started by renderInSubprocess.
+const subprocessParameterEnv = "PROTOC_GEN_JSONSCHEMA_TEST_PARAMETER"
+
+// renderFailure renders the test protos with a parameter that generation is expected to fail with, and returns what it
+// reports.
+func renderFailure(t *testing.T, parameter string) string {
+	t.Helper()
+
+	output, err := renderInSubprocess(t, parameter)
+	require.Error(t, err, "generation didn't fail with %s", parameter)
+	return output
+}
+
+// renderInSubprocess renders the test protos in a subprocess, and returns what it writes to stdout and stderr.
+// protoc-gen-star exits when generation fails, and the log is written to stderr, so the calling test is run again in a
+// subprocess to render them.
+func renderInSubprocess(t *testing.T, parameter string) (string, error) {
+	t.Helper()
+
+	if parameter, ok := os.LookupEnv(subprocessParameterEnv); ok {
+		render(t, parameter)
+		os.Exit(0)
+	}
+
+	names := strings.Split(t.Name(), "/")
+	for i, name := range names {
+		names[i] = "^" + regexp.QuoteMeta(name) + "$"
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run="+strings.Join(names, "/")) //nolint:gosec // The test binary runs itself.
+	cmd.Env = append(os.Environ(), subprocessParameterEnv+"="+parameter)
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// filenames lists the names of the files in the response, failing if generation failed.
+func filenames(t *testing.T, res *pluginpb.CodeGeneratorResponse) []string {
+	t.Helper()
+	require.Empty(t, res.GetError())
+
+	names := make([]string, len(res.GetFile()))
+	for i, file := range res.GetFile() {
+		names[i] = file.GetName()
+	}
+
+	return names
+}
+
+// content returns the content of a file in the response, failing if generation failed or the file wasn't generated.
+func content(t *testing.T, res *pluginpb.CodeGeneratorResponse, filename string) string {
+	t.Helper()
+	require.Contains(t, filenames(t, res), filename)
+
+	for _, file := range res.GetFile() {
+		if file.GetName() == filename {
+			return file.GetContent()
+		}
+	}
+
+	return ""
+}
+
+// document decodes a document from the response, failing if generation failed or the document wasn't generated.
+func document(t *testing.T, res *pluginpb.CodeGeneratorResponse, filename string) map[string]any {
+	t.Helper()
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal([]byte(content(t, res, filename)), &doc), filename)
+	return doc
+}
+
+// lookup returns the value at a path of object keys and array indexes within a decoded document.
+func lookup(t *testing.T, value any, path ...string) any {
+	t.Helper()
+
+	for i, key := range path {
+		switch v := value.(type) {
+		case map[string]any:
+			var ok bool
+			value, ok = v[key]
+			require.Truef(t, ok, "%s not found", path[:i+1])
+
+		case []any:
+			index, err := strconv.Atoi(key)
+			require.NoError(t, err, path[:i+1])
+			require.Lessf(t, index, len(v), "%s not found", path[:i+1])
+			value = v[index]
+
+		default:
+			require.Failf(t, "path not found", "%s is not an object or array", path[:i])
+		}
+	}
+
+	return value
 }
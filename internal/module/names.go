@@ -0,0 +1,122 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"strings"
+
+	pgs "github.com/lyft/protoc-gen-star/v2"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+// fieldNamesMode selects how properties are named.
+type fieldNamesMode string
+
+const (
+	// fieldNamesJSON names properties after the JSON names of fields, which are lowerCamelCase unless overridden by the
+	// json_name option. protojson emits these names by default.
+	fieldNamesJSON fieldNamesMode = "json"
+	// fieldNamesProto names properties after the original names of fields, which protojson emits with UseProtoNames.
+	fieldNamesProto fieldNamesMode = "proto"
+	// fieldNamesBoth accepts properties under either name, as protojson does when parsing.
+	fieldNamesBoth fieldNamesMode = "both"
+)
+
+func (m *Module) parseFieldNamesMode(value string) fieldNamesMode {
+	mode := fieldNamesMode(value)
+	switch mode {
+	case fieldNamesJSON, fieldNamesProto, fieldNamesBoth:
+		return mode
+	default:
+		m.Failf("invalid value %q for field_names parameter (expected %q, %q or %q)", value, fieldNamesJSON, fieldNamesProto, fieldNamesBoth)
+		return ""
+	}
+}
+
+// propertyNames returns the names of the properties that hold the value of a field.
+func (m *Module) propertyNames(field pgs.Field) []string {
+	// protojson encodes extension fields under their fully-qualified names, in brackets.
+	if _, ok := field.(pgs.Extension); ok {
+		return []string{"[" + strings.TrimPrefix(field.FullyQualifiedName(), ".") + "]"}
+	}
+
+	return m.namesForMode(field.Descriptor().GetJsonName(), field.Name().String())
+}
+
+// knownPropertyNames names a property of one of the types whose schemas are defined by hand, given its original name.
+func (m *Module) knownPropertyNames(name string) []string {
+	return m.namesForMode(jsonCamelCase(name), name)
+}
+
+func (m *Module) namesForMode(jsonName, protoName string) []string {
+	switch {
+	case m.fieldNamesMode == fieldNamesProto:
+		return []string{protoName}
+	case m.fieldNamesMode == fieldNamesBoth && jsonName != protoName:
+		return []string{jsonName, protoName}
+	default:
+		return []string{jsonName}
+	}
+}
+
+// addProperty adds a property to an object schema under each of its names, returning any constraints that the object
+// must also satisfy. A property with two names shares a definition between them, and must be given under one name at
+// most, because protojson rejects a field that appears twice.
+func (m *Module) addProperty(schema *jsonschema.ObjectSchema, entity namedEntity, names []string, value jsonschema.Schema, required bool) []jsonschema.NonTrivialSchema {
+	if len(names) == 1 {
+		schema.Properties.Set(names[0], value)
+		if required {
+			schema.Required = append(schema.Required, names[0])
+		}
+
+		return nil
+	}
+
+	ref := m.ref(entity, func() jsonschema.Schema { return value })
+	for _, name := range names {
+		schema.Properties.Set(name, ref)
+	}
+
+	both := jsonschema.NewObjectSchema()
+	both.Required = names
+	constraints := []jsonschema.NonTrivialSchema{jsonschema.Not(both)}
+	if required {
+		constraints = append(constraints, requireAnyOf(names))
+	}
+
+	return constraints
+}
+
+// requireAnyOf returns a schema requiring at least one of the given properties.
+func requireAnyOf(names []string) jsonschema.NonTrivialSchema {
+	schemas := make([]jsonschema.NonTrivialSchema, len(names))
+	for i, name := range names {
+		schema := jsonschema.NewObjectSchema()
+		schema.Required = []string{name}
+		schemas[i] = schema
+	}
+
+	return jsonschema.AnyOf(schemas...)
+}
+
+// jsonCamelCase derives the default JSON name of a field from its original name, in the same way as protoc.
+func jsonCamelCase(name string) string {
+	var builder strings.Builder
+	upper := false
+	for _, c := range name {
+		switch {
+		case c == '_':
+			upper = true
+		case upper && c >= 'a' && c <= 'z':
+			builder.WriteRune(c - 'a' + 'A')
+			upper = false
+		default:
+			builder.WriteRune(c)
+			upper = false
+		}
+	}
+
+	return builder.String()
+}
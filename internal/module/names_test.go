@@ -0,0 +1,90 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldNames(t *testing.T) {
+	testCases := []struct {
+		parameter string
+		names     []string
+	}{
+		{parameter: "", names: []string{"snakeCaseField", "customName"}},
+		{parameter: "field_names=json", names: []string{"snakeCaseField", "customName"}},
+		{parameter: "field_names=proto", names: []string{"snake_case_field", "renamed_field"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			doc := document(t, render(t, tc.parameter), "testproto/FieldNamesTest.schema.json")
+
+			properties := make(map[string]any)
+			for _, name := range tc.names {
+				properties[name] = map[string]any{"type": "string"}
+			}
+
+			require.Equal(t, properties, lookup(t, doc, "properties"))
+		})
+	}
+
+	// The properties of built-in schemas are named in the same way.
+	doc := document(t, render(t, "field_names=proto"), "testproto/GoogleTypeTest.schema.json")
+	require.Contains(t, lookup(t, doc, "definitions", "google.type.Money", "properties"), "currency_code")
+}
+
+func TestFieldNamesBoth(t *testing.T) {
+	res := render(t, "field_names=both")
+
+	// Either name is accepted for each field, but not both at once, and the two share a definition.
+	doc := document(t, res, "testproto/FieldNamesTest.schema.json")
+	require.Equal(t, []any{
+		map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"snakeCaseField":   map[string]any{"$ref": "#/definitions/testproto.FieldNamesTest.snake_case_field"},
+				"snake_case_field": map[string]any{"$ref": "#/definitions/testproto.FieldNamesTest.snake_case_field"},
+				"customName":       map[string]any{"$ref": "#/definitions/testproto.FieldNamesTest.renamed_field"},
+				"renamed_field":    map[string]any{"$ref": "#/definitions/testproto.FieldNamesTest.renamed_field"},
+			},
+		},
+		map[string]any{"not": map[string]any{"type": "object", "required": []any{"snakeCaseField", "snake_case_field"}}},
+		map[string]any{"not": map[string]any{"type": "object", "required": []any{"customName", "renamed_field"}}},
+	}, lookup(t, doc, "allOf"))
+	require.Equal(t, map[string]any{"type": "string"}, lookup(t, doc, "definitions", "testproto.FieldNamesTest.snake_case_field"))
+
+	// A required field can be given by either name.
+	required := document(t, res, "testproto/RequiredModesTest.schema.json")
+	require.Contains(t, lookup(t, required, "allOf"), map[string]any{"anyOf": []any{
+		map[string]any{"type": "object", "required": []any{"ruleField"}},
+		map[string]any{"type": "object", "required": []any{"rule_field"}},
+	}})
+	require.NotContains(t, lookup(t, required, "allOf", "0"), "required")
+}
+
+func TestShortDefinitionNames(t *testing.T) {
+	res := render(t, "definition_names=short")
+
+	require.Subset(t, filenames(t, res), []string{
+		"RecursiveTest.schema.json",
+		"EmbeddedExpression.schema.json",
+		// Names shared by several messages are kept in full.
+		"testproto/NameCollisionTest.schema.json",
+		"testproto/other/NameCollisionTest.schema.json",
+	})
+
+	doc := document(t, res, "RecursiveTest.schema.json")
+	require.Equal(t, "https://protoc-gen-jsonschema.cerbos.dev/RecursiveTest.schema.json", doc["$id"])
+	require.Equal(t, map[string]any{"$ref": "#/definitions/RecursiveNode"}, lookup(t, doc, "properties", "nodes", "items"))
+	require.Contains(t, lookup(t, doc, "definitions"), "RecursiveNode")
+
+	doc = document(t, res, "EmptyEmbeddedTest.schema.json")
+	require.ElementsMatch(t, []string{"EmbeddedExpression", "EmbeddedOperand", "Empty", "FieldMask", "ListValue", "Struct", "Value"}, slices.Collect(maps.Keys(lookup(t, doc, "definitions").(map[string]any))))
+}
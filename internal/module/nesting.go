@@ -4,7 +4,6 @@
 package module
 
 import (
-	"fmt"
 	"strings"
 
 	pgs "github.com/lyft/protoc-gen-star/v2"
@@ -16,41 +15,141 @@ type namedEntity interface {
 	FullyQualifiedName() string
 }
 
+// definitionNamesMode selects how definitions, and the documents of messages, are named.
+type definitionNamesMode string
+
+const (
+	// definitionNamesFull names definitions after the fully-qualified names of the entities they define.
+	definitionNamesFull definitionNamesMode = "full"
+	// definitionNamesShort names definitions after the names of the entities within their parents, unless another
+	// entity in the request has the same name, in which case the fully-qualified name is used instead.
+	definitionNamesShort definitionNamesMode = "short"
+)
+
+func (m *Module) parseDefinitionNamesMode(value string) definitionNamesMode {
+	mode := definitionNamesMode(value)
+	switch mode {
+	case definitionNamesFull, definitionNamesShort:
+		return mode
+	default:
+		m.Failf("invalid value %q for definition_names parameter (expected %q or %q)", value, definitionNamesFull, definitionNamesShort)
+		return ""
+	}
+}
+
+// uniqueShortNames maps the fully-qualified names of the messages, enums and fields declared in the files to their
+// short names, leaving out those whose short names are shared with other entities.
+func uniqueShortNames(files []pgs.File) map[string]string {
+	var entities []pgs.Entity
+	for _, file := range files {
+		for _, message := range file.AllMessages() {
+			entities = append(entities, message)
+			for _, field := range message.Fields() {
+				entities = append(entities, field)
+			}
+		}
+
+		for _, enum := range file.AllEnums() {
+			entities = append(entities, enum)
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, entity := range entities {
+		counts[entity.Name().String()]++
+	}
+
+	names := make(map[string]string)
+	for _, entity := range entities {
+		if name := entity.Name().String(); counts[name] == 1 {
+			names[entity.FullyQualifiedName()] = name
+		}
+	}
+
+	return names
+}
+
+// definitionName returns the name that an entity is defined under.
+func (m *Module) definitionName(entity namedEntity) string {
+	if name, ok := m.shortNames[entity.FullyQualifiedName()]; ok {
+		return name
+	}
+
+	return strings.TrimPrefix(entity.FullyQualifiedName(), ".")
+}
+
 func (m *Module) pushMessage(message pgs.Message) {
-	m.Push(fmt.Sprintf("message:%s", message.Name()))
-	m.Debug("pushMessage")
+	m.enterEntity("message", strings.TrimPrefix(message.FullyQualifiedName(), "."), message)
+	m.debug("pushMessage")
 
 	if m.nestedUnderMessage == nil {
 		m.nestedUnderMessage = message
 		m.definitions = make(map[string]jsonschema.Schema)
+		m.recursive = make(map[string]bool)
 	}
 }
 
 func (m *Module) popMessage(message pgs.Message, schema jsonschema.NonTrivialSchema) {
-	m.Debug("popMessage")
+	m.debug("popMessage")
 	if m.nestedUnder(message) {
-		schema.Define(m.definitions)
+		if key := m.definitionName(message); m.recursive[key] {
+			schema.SetDynamicAnchor(key)
+		}
+
+		schema.Define(m.definitions, m.draft)
 		m.definitions = nil
+		m.recursive = nil
 		m.nestedUnderMessage = nil
 	}
 
-	m.Pop()
+	m.leave()
 }
 
 func (m *Module) ref(entity namedEntity, schema func() jsonschema.Schema) *jsonschema.GenericSchema {
-	m.Debug("ref")
+	m.debug("ref")
+	key := m.definitionName(entity)
 	if m.nestedUnder(entity) {
-		return jsonschema.Ref("#")
+		return m.recursiveRef(key, jsonschema.Ref("#"))
+	}
+
+	definition, ok := m.definitions[key]
+	if !ok {
+		m.definitions[key] = nil // avoid cycles
+		definition = schema()
+		switch {
+		case m.recursive[key]:
+			// A dynamic anchor is also a plain-name fragment, so it can't be given alongside an anchor of the same name.
+			definition = jsonschema.WithDynamicAnchor(definition, key)
+		case m.anchors:
+			definition = jsonschema.WithAnchor(definition, key)
+		}
+
+		m.definitions[key] = definition
+	} else if definition == nil {
+		return m.recursiveRef(key, m.definitionRef(key))
 	}
 
-	key := strings.TrimPrefix(entity.FullyQualifiedName(), ".")
+	return m.definitionRef(key)
+}
+
+// definitionRef returns a reference to a schema under the definitions of the document.
+func (m *Module) definitionRef(key string) *jsonschema.GenericSchema {
+	if m.anchors {
+		return jsonschema.Ref("#" + key)
+	}
+
+	return jsonschema.Ref("#/" + m.draft.DefinitionsKeyword() + "/" + key)
+}
 
-	if _, ok := m.definitions[key]; !ok {
-		m.definitions[key] = jsonschema.True // avoid cycles
-		m.definitions[key] = schema()
+// recursiveRef returns a reference to a schema from within its own definition. If the dynamic_refs parameter is set,
+// the reference is dynamic, so that schemas extending the recursive one also extend the schemas nested within it.
+func (m *Module) recursiveRef(key string, ref *jsonschema.GenericSchema) *jsonschema.GenericSchema {
+	if !m.dynamicRefs {
+		return ref
 	}
 
-	return jsonschema.Ref("#/definitions/" + key)
+	m.recursive[key] = true
+	return jsonschema.DynamicRef("#" + key)
 }
 
 func (m *Module) nestedUnder(entity namedEntity) bool {
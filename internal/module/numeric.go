@@ -5,6 +5,8 @@ package module
 
 import (
 	"encoding/json"
+	"math"
+	"math/big"
 
 	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	pgs "github.com/lyft/protoc-gen-star/v2"
@@ -13,9 +15,12 @@ import (
 	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
 )
 
+// The natural ranges of the 64-bit integer types. Their JSON encodings can hold any value, unlike those of the 32-bit
+// types, which protojson rejects as soon as they exceed the range of an int32 or uint32.
 const (
-	signedDecimalString   = `^-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?$`
-	unsignedDecimalString = `^(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?$`
+	minInt64  = "-9223372036854775808"
+	maxInt64  = "9223372036854775807"
+	maxUint64 = "18446744073709551615"
 )
 
 //nolint:tagliatelle
@@ -29,64 +34,273 @@ type numericRules struct {
 		Lt  jsonschema.Number `json:"Lt,omitempty"`
 		Lte jsonschema.Number `json:"Lte,omitempty"`
 	} `json:"LessThan,omitempty"`
-	In    []jsonschema.Number `json:"in,omitempty"`
-	NotIn []jsonschema.Number `json:"not_in,omitempty"`
+	In     []jsonschema.Number `json:"in,omitempty"`
+	NotIn  []jsonschema.Number `json:"not_in,omitempty"`
+	Finite bool                `json:"finite,omitempty"`
+}
+
+// lowerBound returns the gt or gte rule, and whether it is exclusive.
+func (r *numericRules) lowerBound() (jsonschema.Number, bool) {
+	if r.GreaterThan.Gt != nil {
+		return r.GreaterThan.Gt, true
+	}
+
+	return r.GreaterThan.Gte, false
+}
+
+// upperBound returns the lt or lte rule, and whether it is exclusive.
+func (r *numericRules) upperBound() (jsonschema.Number, bool) {
+	if r.LessThan.Lt != nil {
+		return r.LessThan.Lt, true
+	}
+
+	return r.LessThan.Lte, false
+}
+
+// int64Mode selects how 64-bit integers are represented.
+type int64Mode string
+
+const (
+	// int64AsNumberOrString accepts both numbers and strings, as protojson does when parsing. The two encodings are
+	// combined with anyOf, since many clients send numbers even though protojson produces strings.
+	int64AsNumberOrString int64Mode = "both"
+	// int64AsString only accepts strings, which is how protojson encodes 64-bit integers.
+	int64AsString int64Mode = "string"
+)
+
+func (m *Module) parseInt64Mode(value string) int64Mode {
+	mode := int64Mode(value)
+	switch mode {
+	case int64AsNumberOrString, int64AsString:
+		return mode
+	default:
+		m.Failf("invalid value %q for int64 parameter (expected %q or %q)", value, int64AsNumberOrString, int64AsString)
+		return ""
+	}
 }
 
 func (m *Module) schemaForNumericScalar(numeric pgs.ProtoType, rules *validate.FieldRules) jsonschema.Schema {
-	m.Debug("schemaForNumericScalar")
+	m.debug("schemaForNumericScalar")
 	value := m.valueSchemaForNumericScalar(numeric)
-	schemas := []jsonschema.NonTrivialSchema{m.stringValueSchemaForNumericScalar(numeric, value)}
+	stringValue := m.stringValueSchemaForNumericScalar(numeric)
+	schemas := []jsonschema.NonTrivialSchema{m.combineNumericSchemas(value, stringValue)}
 	r := m.numericRules(numeric, rules)
 
 	//nolint:nestif
 	if r != nil {
 		if r.Const != nil {
 			value.Const = r.Const
+			if stringValue != nil {
+				stringValue.Const = jsonschema.String(string(r.Const))
+			}
 		}
 
-		if r.GreaterThan.Gt != nil {
-			value.ExclusiveMinimum = r.GreaterThan.Gt
-		}
-
-		if r.GreaterThan.Gte != nil {
-			value.Minimum = r.GreaterThan.Gte
+		m.applyNumericBounds(value, r)
+		if stringValue != nil {
+			m.applyIntegerStringBounds(numeric, stringValue, r)
 		}
 
 		if len(r.In) > 0 {
 			value.Enum = r.In
+			if stringValue != nil {
+				stringValue.Enum = numbersToStrings(r.In)
+			}
 		}
 
-		if r.LessThan.Lt != nil {
-			value.ExclusiveMaximum = r.LessThan.Lt
+		if len(r.NotIn) > 0 {
+			if stringValue == nil || m.int64Mode != int64AsString {
+				in := jsonschema.NewNumberSchema()
+				in.Enum = r.NotIn
+				schemas = append(schemas, jsonschema.Not(in))
+			}
+
+			if stringValue != nil {
+				stringIn := jsonschema.NewStringSchema()
+				stringIn.Enum = numbersToStrings(r.NotIn)
+				schemas = append(schemas, jsonschema.Not(stringIn))
+			}
 		}
 
-		if r.LessThan.Lte != nil {
-			value.Maximum = r.LessThan.Lte
+		if r.Finite {
+			// JSON numbers cannot represent NaN or infinity, so this only documents the rule.
+			value.SetExtension("x-finite", true)
 		}
+	}
 
-		if len(r.NotIn) > 0 {
-			in := jsonschema.NewNumberSchema()
-			in.Enum = r.NotIn
+	if nonFinite := m.nonFiniteSchemaForNumericScalar(numeric, r); nonFinite != nil {
+		schemas[0] = jsonschema.AnyOf(schemas[0], nonFinite)
+	}
+
+	return jsonschema.AllOf(schemas...)
+}
 
-			schemas = append(schemas, jsonschema.Not(in))
+// nonFiniteSchemaForNumericScalar returns the schema for the strings that protojson uses to encode the non-finite
+// values of floating-point types, restricted to those that satisfy the rules. It returns nil for other types, when the
+// option to accept them is off, or when the rules exclude them all.
+func (m *Module) nonFiniteSchemaForNumericScalar(numeric pgs.ProtoType, r *numericRules) *jsonschema.StringSchema {
+	m.debug("nonFiniteSchemaForNumericScalar")
+	if !m.nonFiniteFloats || (numeric != pgs.DoubleT && numeric != pgs.FloatT) {
+		return nil
+	}
+
+	values := []string{"NaN", "Infinity", "-Infinity"}
+
+	if r != nil {
+		if r.Finite || r.Const != nil || len(r.In) > 0 {
+			return nil
+		}
+
+		// NaN fails every comparison, and infinity only fails comparisons in one direction. An upper bound below the lower
+		// bound excludes the range between them, so both infinities are outside it.
+		lower, _ := r.lowerBound()
+		upper, _ := r.upperBound()
+		switch {
+		case lower != nil && upper != nil && upper.Less(lower):
+			values = []string{"Infinity", "-Infinity"}
+		case lower != nil && upper != nil:
+			return nil
+		case lower != nil:
+			values = []string{"Infinity"}
+		case upper != nil:
+			values = []string{"-Infinity"}
 		}
 	}
 
-	return jsonschema.AllOf(schemas...)
+	schema := jsonschema.NewStringSchema()
+	schema.Enum = values
+	return schema
+}
+
+// applyNumericBounds applies the gt, gte, lt and lte rules. protovalidate treats an upper bound below the lower bound as
+// excluding the range between them, rather than as a contradiction.
+func (m *Module) applyNumericBounds(value *jsonschema.NumberSchema, r *numericRules) {
+	m.debug("applyNumericBounds")
+	lower, lowerExclusive := r.lowerBound()
+	upper, upperExclusive := r.upperBound()
+
+	if lower != nil && upper != nil && upper.Less(lower) {
+		above := jsonschema.NewNumberSchema()
+		m.setLowerBound(above, lower, lowerExclusive)
+
+		below := jsonschema.NewNumberSchema()
+		m.setUpperBound(below, upper, upperExclusive)
+
+		value.AnyOf = []jsonschema.NonTrivialSchema{above, below}
+		return
+	}
+
+	if lower != nil {
+		m.setLowerBound(value, lower, lowerExclusive)
+	}
+
+	if upper != nil {
+		m.setUpperBound(value, upper, upperExclusive)
+	}
+}
+
+// applyIntegerStringBounds applies the gt, gte, lt and lte rules to the string encoding of a 64-bit integer, by
+// replacing its pattern with one that only matches integers in range.
+func (m *Module) applyIntegerStringBounds(numeric pgs.ProtoType, stringValue *jsonschema.StringSchema, r *numericRules) {
+	m.debug("applyIntegerStringBounds")
+	lower, lowerExclusive := r.lowerBound()
+	upper, upperExclusive := r.upperBound()
+	if lower == nil && upper == nil {
+		return
+	}
+
+	typeRange := m.integerTypeRange(numeric)
+	bounded := typeRange
+
+	if lower != nil {
+		bounded.minimum = m.bigInt(lower)
+		if lowerExclusive {
+			bounded.minimum.Add(bounded.minimum, bigOne)
+		}
+	}
+
+	if upper != nil {
+		bounded.maximum = m.bigInt(upper)
+		if upperExclusive {
+			bounded.maximum.Sub(bounded.maximum, bigOne)
+		}
+	}
+
+	if lower != nil && upper != nil && upper.Less(lower) {
+		stringValue.Pattern = integerRangePattern(
+			integerRange{minimum: bounded.minimum, maximum: typeRange.maximum},
+			integerRange{minimum: typeRange.minimum, maximum: bounded.maximum},
+		)
+		return
+	}
+
+	stringValue.Pattern = integerRangePattern(bounded)
+}
+
+// integerTypeRange returns the range of values that an integer type can hold.
+func (m *Module) integerTypeRange(numeric pgs.ProtoType) integerRange {
+	switch numeric {
+	case pgs.Fixed32T, pgs.UInt32T:
+		return integerRange{minimum: new(big.Int), maximum: big.NewInt(math.MaxUint32)}
+
+	case pgs.Int32T, pgs.SFixed32, pgs.SInt32:
+		return integerRange{minimum: big.NewInt(math.MinInt32), maximum: big.NewInt(math.MaxInt32)}
+
+	case pgs.Fixed64T, pgs.UInt64T:
+		return integerRange{minimum: new(big.Int), maximum: m.bigInt(jsonschema.Number(maxUint64))}
+
+	default:
+		return integerRange{minimum: m.bigInt(jsonschema.Number(minInt64)), maximum: m.bigInt(jsonschema.Number(maxInt64))}
+	}
+}
+
+func (m *Module) bigInt(number jsonschema.Number) *big.Int {
+	value, ok := new(big.Int).SetString(string(number), 10)
+	if !ok {
+		m.Failf("invalid integer %s", number)
+	}
+
+	return value
+}
+
+func (m *Module) setLowerBound(schema *jsonschema.NumberSchema, bound jsonschema.Number, exclusive bool) {
+	if exclusive {
+		schema.SetExclusiveMinimum(bound, m.draft)
+	} else {
+		schema.Minimum = bound
+	}
+}
+
+func (m *Module) setUpperBound(schema *jsonschema.NumberSchema, bound jsonschema.Number, exclusive bool) {
+	if exclusive {
+		schema.SetExclusiveMaximum(bound, m.draft)
+	} else {
+		schema.Maximum = bound
+	}
 }
 
 func (m *Module) valueSchemaForNumericScalar(numeric pgs.ProtoType) *jsonschema.NumberSchema {
-	m.Debug("valueSchemaForNumericScalar")
+	m.debug("valueSchemaForNumericScalar")
 	switch numeric {
-	case pgs.Fixed32T, pgs.UInt32T, pgs.Fixed64T, pgs.UInt64T:
+	case pgs.Fixed32T, pgs.UInt32T:
+		schema := jsonschema.NewIntegerSchema()
+		schema.Minimum = jsonschema.Number("0")
+		return schema
+
+	case pgs.Fixed64T, pgs.UInt64T:
 		schema := jsonschema.NewIntegerSchema()
 		schema.Minimum = jsonschema.Number("0")
+		schema.Maximum = jsonschema.Number(maxUint64)
 		return schema
 
-	case pgs.Int32T, pgs.SFixed32, pgs.SInt32, pgs.Int64T, pgs.SFixed64, pgs.SInt64:
+	case pgs.Int32T, pgs.SFixed32, pgs.SInt32:
 		return jsonschema.NewIntegerSchema()
 
+	case pgs.Int64T, pgs.SFixed64, pgs.SInt64:
+		schema := jsonschema.NewIntegerSchema()
+		schema.Minimum = jsonschema.Number(minInt64)
+		schema.Maximum = jsonschema.Number(maxInt64)
+		return schema
+
 	case pgs.DoubleT, pgs.FloatT:
 		return jsonschema.NewNumberSchema()
 
@@ -96,29 +310,45 @@ func (m *Module) valueSchemaForNumericScalar(numeric pgs.ProtoType) *jsonschema.
 	}
 }
 
-func (m *Module) stringValueSchemaForNumericScalar(numeric pgs.ProtoType, value *jsonschema.NumberSchema) jsonschema.NonTrivialSchema {
-	m.Debug("stringValueSchemaForNumericScalar")
-	var pattern string
-
+// stringValueSchemaForNumericScalar returns the schema for the string encoding of 64-bit integers, or nil for other types.
+func (m *Module) stringValueSchemaForNumericScalar(numeric pgs.ProtoType) *jsonschema.StringSchema {
+	m.debug("stringValueSchemaForNumericScalar")
 	switch numeric {
-	case pgs.Fixed64T, pgs.UInt64T:
-		pattern = unsignedDecimalString
-
-	case pgs.Int64T, pgs.SFixed64, pgs.SInt64:
-		pattern = signedDecimalString
+	case pgs.Fixed64T, pgs.UInt64T, pgs.Int64T, pgs.SFixed64, pgs.SInt64:
+		// protojson always produces canonical decimal strings, although it also accepts exponents when parsing.
+		stringValue := jsonschema.NewStringSchema()
+		stringValue.Pattern = integerRangePattern(m.integerTypeRange(numeric))
+		return stringValue
 
 	default:
+		return nil
+	}
+}
+
+func (m *Module) combineNumericSchemas(value *jsonschema.NumberSchema, stringValue *jsonschema.StringSchema) jsonschema.NonTrivialSchema {
+	m.debug("combineNumericSchemas")
+	if stringValue == nil {
 		return value
 	}
 
-	stringValue := jsonschema.NewStringSchema()
-	stringValue.Pattern = pattern
+	if m.int64Mode == int64AsString {
+		return stringValue
+	}
+
+	return jsonschema.AnyOf(value, stringValue)
+}
 
-	return jsonschema.OneOf(value, stringValue)
+func numbersToStrings(numbers []jsonschema.Number) []string {
+	values := make([]string, len(numbers))
+	for i, number := range numbers {
+		values[i] = string(number)
+	}
+
+	return values
 }
 
 func (m *Module) numericRules(numeric pgs.ProtoType, rules *validate.FieldRules) *numericRules {
-	m.Debug("numericRules")
+	m.debug("numericRules")
 	var source proto.Message
 
 	switch numeric {
@@ -166,10 +396,14 @@ func (m *Module) numericRules(numeric pgs.ProtoType, rules *validate.FieldRules)
 		return nil
 	}
 
-	if source == nil {
+	if source == nil || !source.ProtoReflect().IsValid() {
 		return nil
 	}
 
+	// Examples don't constrain the value, and they may be non-finite floats that can't be encoded as JSON numbers.
+	source = proto.Clone(source)
+	source.ProtoReflect().Clear(source.ProtoReflect().Descriptor().Fields().ByName("example"))
+
 	data, err := json.Marshal(source)
 	m.CheckErr(err, "failed to marshal numeric validation rules to JSON")
 
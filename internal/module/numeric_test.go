@@ -0,0 +1,125 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExclusiveBounds(t *testing.T) {
+	testCases := []struct {
+		draft  string
+		schema string
+		bounds map[string]any
+	}{
+		{
+			draft:  "draft-04",
+			schema: "http://json-schema.org/draft-04/schema#",
+			bounds: map[string]any{"type": "integer", "minimum": float64(5), "exclusiveMinimum": true, "maximum": float64(10), "exclusiveMaximum": true},
+		},
+		{
+			draft:  "draft-06",
+			schema: "http://json-schema.org/draft-06/schema#",
+			bounds: map[string]any{"type": "integer", "minimum": float64(0), "exclusiveMinimum": float64(5), "exclusiveMaximum": float64(10)},
+		},
+		{
+			draft:  "2020-12",
+			schema: "https://json-schema.org/draft/2020-12/schema",
+			bounds: map[string]any{"type": "integer", "minimum": float64(0), "exclusiveMinimum": float64(5), "exclusiveMaximum": float64(10)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.draft, func(t *testing.T) {
+			// Other test messages use options that draft-04 doesn't support.
+			doc := document(t, render(t, "include=testproto.Uint32RulesTest,draft="+tc.draft), "testproto/Uint32RulesTest.schema.json")
+			require.Equal(t, tc.schema, doc["$schema"])
+			require.Equal(t, tc.bounds, lookup(t, doc, "properties", "rangeField"))
+		})
+	}
+}
+
+func TestInt64AsString(t *testing.T) {
+	doc := document(t, render(t, "int64=string"), "testproto/Int64RulesTest.schema.json")
+	properties := lookup(t, doc, "properties")
+
+	rangeField := lookup(t, properties, "rangeField").(map[string]any)
+	require.Equal(t, "string", rangeField["type"])
+	requireMatches(t, rangeField["pattern"], []string{"100", "555", "1000"}, []string{"99", "1001", "0100", "-100", "1e3"})
+
+	require.Equal(t, []any{"1", "2", "3"}, lookup(t, properties, "inField", "enum"))
+}
+
+// requireMatches checks that a pattern matches each of the accepted strings and none of the rejected ones.
+func requireMatches(t *testing.T, pattern any, accepted, rejected []string) {
+	t.Helper()
+
+	require.IsType(t, "", pattern)
+	re := regexp.MustCompile(pattern.(string))
+	for _, s := range accepted {
+		require.True(t, re.MatchString(s), "%q against %s", s, pattern)
+	}
+
+	for _, s := range rejected {
+		require.False(t, re.MatchString(s), "%q against %s", s, pattern)
+	}
+}
+
+func TestInt64AsNumberOrString(t *testing.T) {
+	for _, parameter := range []string{"", "int64=both"} {
+		t.Run(parameter, func(t *testing.T) {
+			doc := document(t, render(t, parameter), "testproto/Int64RulesTest.schema.json")
+			alternatives := lookup(t, doc, "properties", "rangeField", "anyOf").([]any)
+			require.Len(t, alternatives, 2)
+
+			require.Equal(t, map[string]any{"type": "integer", "minimum": float64(0), "exclusiveMinimum": float64(99), "maximum": float64(1000)}, alternatives[0])
+			require.Equal(t, "string", lookup(t, alternatives[1], "type"))
+			requireMatches(t, lookup(t, alternatives[1], "pattern"), []string{"100", "1000"}, []string{"99", "1001"})
+		})
+	}
+}
+
+func TestNonFiniteFloats(t *testing.T) {
+	testCases := []struct {
+		parameter  string
+		unbounded  map[string]any
+		positive   map[string]any
+		finiteOnly map[string]any
+	}{
+		{
+			parameter:  "",
+			unbounded:  map[string]any{"type": "number"},
+			positive:   map[string]any{"type": "number", "exclusiveMinimum": float64(0)},
+			finiteOnly: map[string]any{"type": "number", "x-finite": true},
+		},
+		{
+			parameter: "non_finite_floats=true",
+			unbounded: map[string]any{"anyOf": []any{
+				map[string]any{"type": "number"},
+				map[string]any{"type": "string", "enum": []any{"NaN", "Infinity", "-Infinity"}},
+			}},
+			// Only the non-finite values that satisfy the bounds are accepted.
+			positive: map[string]any{"anyOf": []any{
+				map[string]any{"type": "number", "exclusiveMinimum": float64(0)},
+				map[string]any{"type": "string", "enum": []any{"Infinity"}},
+			}},
+			finiteOnly: map[string]any{"type": "number", "x-finite": true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			properties := lookup(t, document(t, render(t, tc.parameter), "testproto/FloatRulesTest.schema.json"), "properties")
+
+			unbounded := lookup(t, properties, "exampleField").(map[string]any)
+			delete(unbounded, "examples")
+			require.Equal(t, tc.unbounded, unbounded)
+			require.Equal(t, tc.positive, lookup(t, properties, "positiveDouble"))
+			require.Equal(t, tc.finiteOnly, lookup(t, properties, "finiteDouble"))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	pgs "github.com/lyft/protoc-gen-star/v2"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+// oneOfMode selects how oneofs are represented.
+type oneOfMode string
+
+const (
+	// oneOfStrict allows at most one member of a oneof to be present, as protojson does, and exactly one if the oneof
+	// is required.
+	oneOfStrict oneOfMode = "one_of"
+	// oneOfAnyOf requires at least one member of a required oneof to be present, without limiting how many are.
+	oneOfAnyOf oneOfMode = "any_of"
+	// oneOfFlat doesn't constrain the members of oneofs, which are only represented as properties.
+	oneOfFlat oneOfMode = "flat"
+)
+
+func (m *Module) parseOneOfMode(value string) oneOfMode {
+	mode := oneOfMode(value)
+	switch mode {
+	case oneOfStrict, oneOfAnyOf, oneOfFlat:
+		return mode
+	default:
+		m.Failf("invalid value %q for oneof parameter (expected %q, %q or %q)", value, oneOfStrict, oneOfAnyOf, oneOfFlat)
+		return ""
+	}
+}
+
+// schemaForOneOf returns the constraint that a oneof places on the presence of its members, or nil if it doesn't
+// place one.
+func (m *Module) schemaForOneOf(oneOf pgs.OneOf, disabled bool) jsonschema.NonTrivialSchema {
+	m.debug("schemaForOneOf")
+	required := !disabled && m.oneOfRequired(oneOf)
+
+	var members []jsonschema.NonTrivialSchema
+	for _, field := range oneOf.Fields() {
+		if !m.fieldOptions(field).GetHide() {
+			members = append(members, requireAnyOf(m.propertyNames(field)))
+		}
+	}
+
+	// A oneof whose members are all hidden can only be set by leaving them out.
+	if len(members) == 0 {
+		return nil
+	}
+
+	switch m.oneOfMode {
+	case oneOfStrict:
+		if required {
+			return jsonschema.OneOf(members...)
+		}
+
+		if len(members) == 1 {
+			return nil
+		}
+
+		// Exactly one of the members, or none of them.
+		return jsonschema.OneOf(append(members, jsonschema.Not(jsonschema.AnyOf(members...)))...)
+
+	case oneOfAnyOf:
+		if required {
+			return jsonschema.AnyOf(members...)
+		}
+
+		return nil
+
+	case oneOfFlat:
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+func (m *Module) oneOfRequired(oneOf pgs.OneOf) bool {
+	m.debug("oneOfRequired")
+	rules := validate.OneofRules{}
+	ok, err := oneOf.Extension(validate.E_Oneof, &rules)
+	m.CheckErr(err, "unable to read oneOf option")
+
+	if ok {
+		return rules.GetRequired()
+	}
+
+	return m.legacyOneOfRequired(oneOf)
+}
@@ -0,0 +1,64 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOneOfModes(t *testing.T) {
+	present := func(name string) any {
+		return map[string]any{"type": "object", "required": []any{name}}
+	}
+
+	testCases := []struct {
+		parameter string
+		// required is the constraint on OneOfRulesTest, whose oneof is required, or nil if there isn't one.
+		required any
+		// optional is the constraint on EmptyOneOfRulesTest, whose oneof isn't required, or nil if there isn't one.
+		optional any
+	}{
+		{
+			parameter: "",
+			required:  map[string]any{"oneOf": []any{present("boolField"), present("stringField")}},
+			optional: map[string]any{"oneOf": []any{
+				present("boolField"),
+				present("stringField"),
+				map[string]any{"not": map[string]any{"anyOf": []any{present("boolField"), present("stringField")}}},
+			}},
+		},
+		{
+			parameter: "oneof=any_of",
+			required:  map[string]any{"anyOf": []any{present("boolField"), present("stringField")}},
+		},
+		{
+			parameter: "oneof=flat",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			res := render(t, tc.parameter)
+			requireOneOfConstraint(t, tc.required, document(t, res, "testproto/OneOfRulesTest.schema.json"))
+			requireOneOfConstraint(t, tc.optional, document(t, res, "testproto/EmptyOneOfRulesTest.schema.json"))
+		})
+	}
+}
+
+// requireOneOfConstraint checks the constraint that a document's oneof adds to its object schema, if there is one.
+func requireOneOfConstraint(t *testing.T, constraint any, doc map[string]any) {
+	t.Helper()
+
+	if constraint == nil {
+		require.NotContains(t, doc, "allOf")
+		require.Equal(t, "object", doc["type"])
+		return
+	}
+
+	allOf := lookup(t, doc, "allOf").([]any)
+	require.Len(t, allOf, 2)
+	require.Equal(t, constraint, allOf[1])
+}
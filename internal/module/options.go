@@ -0,0 +1,218 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"encoding/json"
+	"strings"
+
+	pgs "github.com/lyft/protoc-gen-star/v2"
+
+	jsonschemapb "github.com/cerbos/protoc-gen-jsonschema/gen/pb/jsonschema"
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+// fileOptions returns the options that customise the documents generated for a file, which are empty if it doesn't set
+// any.
+func (m *Module) fileOptions(file pgs.File) *jsonschemapb.FileOptions {
+	m.debug("fileOptions")
+	options := &jsonschemapb.FileOptions{}
+	_, err := file.Extension(jsonschemapb.E_File, options)
+	m.CheckErr(err, "unable to read jsonschema options from file")
+	return options
+}
+
+// useFileOptions configures the documents about to be generated according to the options of the files that they are
+// generated for, falling back to the parameters for anything the options don't set.
+func (m *Module) useFileOptions(options *jsonschemapb.FileOptions) {
+	m.draft = m.defaultDraft
+	if draft := options.GetDraft(); draft != "" {
+		m.draft = m.parseDraft("draft option of the file", draft)
+		m.checkDraft()
+	}
+
+	m.idBase = options.GetIdBase()
+	if m.idBase != "" && !strings.HasSuffix(m.idBase, "/") {
+		m.idBase += "/"
+	}
+}
+
+// messageOptions returns the options that customise the schema generated for a message, which are empty if it doesn't
+// set any.
+func (m *Module) messageOptions(message pgs.Message) *jsonschemapb.MessageOptions {
+	m.debug("messageOptions")
+	options := &jsonschemapb.MessageOptions{}
+	_, err := message.Extension(jsonschemapb.E_Message, options)
+	m.CheckErr(err, "unable to read jsonschema options from message")
+	return options
+}
+
+// fieldOptions returns the options that customise the schema generated for a field, which are empty if it doesn't set
+// any.
+func (m *Module) fieldOptions(field pgs.Field) *jsonschemapb.FieldOptions {
+	m.debug("fieldOptions")
+	options := &jsonschemapb.FieldOptions{}
+	_, err := field.Extension(jsonschemapb.E_Field, options)
+	m.CheckErr(err, "unable to read jsonschema options from field")
+	return options
+}
+
+// enumOptions returns the options that customise the schema generated for an enum, which are empty if it doesn't set
+// any.
+func (m *Module) enumOptions(enum pgs.Enum) *jsonschemapb.EnumOptions {
+	m.debug("enumOptions")
+	options := &jsonschemapb.EnumOptions{}
+	_, err := enum.Extension(jsonschemapb.E_Enum, options)
+	m.CheckErr(err, "unable to read jsonschema options from enum")
+	return options
+}
+
+// applySchemaOption combines the schema generated for a field with the schema given as JSON in the field's options, or
+// replaces the generated schema with it. The given schema is wrapped in allOf, so that keywords added to the field's
+// schema afterwards, such as examples, don't clash with its own.
+func (m *Module) applySchemaOption(options *jsonschemapb.FieldOptions, schema jsonschema.Schema) jsonschema.Schema {
+	if options.GetSchema() == "" {
+		return schema
+	}
+
+	raw, err := jsonschema.Raw([]byte(options.GetSchema()))
+	m.CheckErr(err, "invalid schema option of field")
+
+	generated, ok := schema.(jsonschema.NonTrivialSchema)
+	switch {
+	case options.GetReplaceSchema() || schema == jsonschema.True:
+		return &jsonschema.GenericSchema{AllOf: []jsonschema.NonTrivialSchema{raw}}
+	case ok:
+		return &jsonschema.GenericSchema{AllOf: []jsonschema.NonTrivialSchema{generated, raw}}
+	default:
+		// Nothing satisfies the generated schema, so nothing can satisfy both.
+		return schema
+	}
+}
+
+// schemaForStructSchemaOption returns the schema given as JSON in the options of a google.protobuf.Struct field, which
+// replaces the schema of any object, for structs that have a known shape. Since protojson encodes structs as objects,
+// the given schema is combined with one that only accepts objects.
+func (m *Module) schemaForStructSchemaOption(field pgs.Field, options *jsonschemapb.FieldOptions) jsonschema.Schema {
+	embed := field.Type().Embed()
+	if embed == nil || embed.WellKnownType() != pgs.StructWKT || field.Type().IsRepeated() || field.Type().IsMap() {
+		m.Fail("struct_schema option only applies to singular google.protobuf.Struct fields")
+	}
+
+	raw, err := jsonschema.Raw([]byte(options.GetStructSchema()))
+	m.CheckErr(err, "invalid struct_schema option of field")
+
+	object := jsonschema.NewObjectSchema()
+	object.AllOf = []jsonschema.NonTrivialSchema{raw}
+	return object
+}
+
+// applyAccessOptions annotates the schema of a field as read-only or write-only according to the field's options.
+func (m *Module) applyAccessOptions(options *jsonschemapb.FieldOptions, schema jsonschema.Schema) jsonschema.Schema {
+	if !options.GetReadOnly() && !options.GetWriteOnly() {
+		return schema
+	}
+
+	switch {
+	case options.GetReadOnly() && options.GetWriteOnly():
+		m.Fail("read_only and write_only options can't both be set, since the field would never be given")
+	case !m.draft.AccessKeywords():
+		m.Failf("read_only and write_only options require draft %s or later", jsonschema.Draft07)
+	case options.GetReadOnly():
+		return jsonschema.WithReadOnly(schema)
+	}
+
+	return jsonschema.WithWriteOnly(schema)
+}
+
+// applyFormatOption adds the format given by the options of a string field to its schema. If the format parameter is
+// pattern, formats that the generator has patterns for are replaced with them, as they are for validation rules, and
+// other formats are still given as keywords.
+func (m *Module) applyFormatOption(field pgs.Field, options *jsonschemapb.FieldOptions, schema jsonschema.Schema) jsonschema.Schema {
+	if options.GetFormat() == "" {
+		return schema
+	}
+
+	if field.Type().ProtoType() != pgs.StringT || field.Type().IsRepeated() {
+		m.Fail("format option only applies to singular string fields")
+	}
+
+	generated, ok := schema.(jsonschema.NonTrivialSchema)
+	if !ok {
+		return schema
+	}
+
+	constraint, schemas := stringKeywords(generated, func(s *jsonschema.StringSchema) bool { return s.Format == "" })
+	switch format := jsonschema.StringFormat(options.GetFormat()); format {
+	case jsonschema.StringFormatEmail, jsonschema.StringFormatHostname, jsonschema.StringFormatIPv4, jsonschema.StringFormatIPv6,
+		jsonschema.StringFormatURI, jsonschema.StringFormatURIReference:
+		schemas = m.applyFormat(constraint, format, schemas)
+
+	default:
+		constraint.Format = format
+	}
+
+	return jsonschema.AllOf(schemas...)
+}
+
+// parseExamples parses the example values given as JSON in options. The values are kept as they were given, so that
+// the keys of objects stay in order.
+func (m *Module) parseExamples(examples []string) []any {
+	values := make([]any, len(examples))
+	for i, example := range examples {
+		if !json.Valid([]byte(example)) {
+			m.Failf("invalid example %q in options (expected a JSON value)", example)
+		}
+
+		values[i] = json.RawMessage(example)
+	}
+
+	return values
+}
+
+// applyContentOptions adds the media type and schema of the content given by the options of a string or bytes field to
+// its schema.
+func (m *Module) applyContentOptions(field pgs.Field, options *jsonschemapb.FieldOptions, schema jsonschema.Schema) jsonschema.Schema {
+	if options.GetContentMediaType() == "" && options.GetContentSchema() == "" {
+		return schema
+	}
+
+	switch {
+	case (field.Type().ProtoType() != pgs.StringT && field.Type().ProtoType() != pgs.BytesT) || field.Type().IsRepeated():
+		m.Fail("content options only apply to singular string and bytes fields")
+	case options.GetContentMediaType() == "":
+		m.Fail("content_schema option requires content_media_type, since the content can't be decoded without it")
+	case !m.draft.ContentKeywords():
+		m.Failf("content_media_type option requires draft %s or later", jsonschema.Draft07)
+	case options.GetContentSchema() != "" && !m.draft.ContentSchemaKeyword():
+		m.Failf("content_schema option requires draft %s or later", jsonschema.Draft201909)
+	}
+
+	generated, ok := schema.(jsonschema.NonTrivialSchema)
+	if !ok {
+		return schema
+	}
+
+	constraint, schemas := stringKeywords(generated, func(s *jsonschema.StringSchema) bool { return s.ContentMediaType == "" })
+	constraint.ContentMediaType = options.GetContentMediaType()
+	if options.GetContentSchema() != "" {
+		contentSchema, err := jsonschema.Raw([]byte(options.GetContentSchema()))
+		m.CheckErr(err, "invalid content_schema option of field")
+		constraint.ContentSchema = contentSchema
+	}
+
+	return jsonschema.AllOf(schemas...)
+}
+
+// stringKeywords returns the schema that keywords given by the options of a string field are added to, along with the
+// schemas that values of the field must satisfy. If the field's schema is a plain string schema that accepts the
+// keywords, they are added to it, and otherwise to a separate schema that is combined with it.
+func stringKeywords(schema jsonschema.NonTrivialSchema, accepts func(*jsonschema.StringSchema) bool) (*jsonschema.StringSchema, []jsonschema.NonTrivialSchema) {
+	if constraint, ok := schema.(*jsonschema.StringSchema); ok && accepts(constraint) {
+		return constraint, []jsonschema.NonTrivialSchema{schema}
+	}
+
+	constraint := &jsonschema.StringSchema{}
+	return constraint, []jsonschema.NonTrivialSchema{schema, constraint}
+}
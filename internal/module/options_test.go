@@ -0,0 +1,159 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	jsonschemapb "github.com/cerbos/protoc-gen-jsonschema/gen/pb/jsonschema"
+)
+
+func TestSchemaOption(t *testing.T) {
+	properties := lookup(t, document(t, render(t, ""), "testproto/RawSchemaTest.schema.json"), "properties")
+
+	require.Equal(t, map[string]any{"allOf": []any{
+		map[string]any{"type": "string"},
+		map[string]any{"pattern": "^[a-z]+$", "contentMediaType": "text/plain"},
+	}}, lookup(t, properties, "mergedField"))
+
+	require.Equal(t, map[string]any{"allOf": []any{
+		map[string]any{"type": "string", "contentSchema": map[string]any{"type": "object"}},
+	}}, lookup(t, properties, "replacedField"))
+}
+
+func TestFormatOption(t *testing.T) {
+	properties := lookup(t, document(t, render(t, ""), "testproto/FormatOptionTest.schema.json"), "properties")
+
+	require.Equal(t, map[string]any{"type": "string", "format": "date"}, lookup(t, properties, "dateField"))
+	require.Equal(t, map[string]any{"type": "string", "format": "hostname"}, lookup(t, properties, "hostnameField"))
+
+	// The format implied by the validation rules is kept alongside the one from the option.
+	require.Equal(t, map[string]any{"allOf": []any{
+		map[string]any{"type": "string", "format": "email"},
+		map[string]any{"format": "idn-email"},
+	}}, lookup(t, properties, "constrainedField"))
+
+	// Formats that the generator has patterns for are replaced with them, and the others are kept.
+	properties = lookup(t, document(t, render(t, "formats=pattern"), "testproto/FormatOptionTest.schema.json"), "properties")
+	require.Equal(t, map[string]any{"type": "string", "format": "date"}, lookup(t, properties, "dateField"))
+	require.NotContains(t, lookup(t, properties, "hostnameField"), "format")
+	requireMatches(t, lookup(t, properties, "hostnameField", "allOf", "1", "pattern"), []string{"example.com", "localhost"}, []string{"-example.com", "example..com"})
+}
+
+func TestExamplesOption(t *testing.T) {
+	doc := document(t, render(t, ""), "testproto/ExamplesOptionTest.schema.json")
+
+	require.Equal(t, []any{map[string]any{"name": "Alice", "age": float64(42)}}, lookup(t, doc, "examples"))
+	require.Equal(t, []any{float64(42)}, lookup(t, doc, "properties", "age", "examples"))
+
+	// Examples from the option follow those from the validation rules.
+	require.Equal(t, []any{"Bob", "Alice"}, lookup(t, doc, "properties", "name", "examples"))
+}
+
+func TestTitleAndDescriptionOptions(t *testing.T) {
+	res := render(t, "")
+	doc := document(t, res, "testproto/TextOptionTest.schema.json")
+
+	require.Equal(t, "Text options", doc["title"])
+	require.Equal(t, "A message whose schema has its own title and description.", doc["description"])
+	require.Equal(t, map[string]any{"title": "Name", "description": "The name shown to users.", "type": "string"}, lookup(t, doc, "properties", "name"))
+
+	require.Equal(t, map[string]any{"description": "The parent, if there is one.", "allOf": []any{map[string]any{"$ref": "#"}}}, lookup(t, doc, "properties", "parent"))
+
+	// The comments are aimed at maintainers, and the options take their place.
+	raw := content(t, res, "testproto/TextOptionTest.schema.json")
+	require.NotContains(t, raw, "only described to its maintainers")
+	require.NotContains(t, raw, "Maintainers only")
+}
+
+func TestRequiredOption(t *testing.T) {
+	// The option requires fields regardless of how the other fields are treated.
+	for _, parameter := range []string{"", "required=none", "optional=nullable"} {
+		t.Run(parameter, func(t *testing.T) {
+			doc := document(t, render(t, parameter), "testproto/RequiredOptionTest.schema.json")
+			require.Equal(t, []any{"name", "nickname"}, doc["required"])
+			require.Equal(t, map[string]any{"type": "string"}, lookup(t, doc, "properties", "nickname"))
+		})
+	}
+}
+
+func TestContentOptions(t *testing.T) {
+	properties := lookup(t, document(t, render(t, ""), "testproto/ContentOptionsTest.schema.json"), "properties")
+
+	require.Equal(t, map[string]any{"type": "string", "contentMediaType": "application/json"}, lookup(t, properties, "jsonField"))
+	require.Equal(t, "base64", lookup(t, properties, "imageField", "contentEncoding"))
+	require.Equal(t, "image/png", lookup(t, properties, "imageField", "contentMediaType"))
+
+	output := renderFailure(t, "draft=draft-06,include=testproto.ContentOptionsTest")
+	require.Contains(t, output, "content_media_type option requires draft draft-07 or later")
+}
+
+func TestContentSchemaOption(t *testing.T) {
+	// content_schema requires a later draft than the test protos are rendered with by default, so it's only set here.
+	res := renderEdited(t, "draft=2020-12,include=testproto.ContentOptionsTest", func(req *pluginpb.CodeGeneratorRequest) {
+		options := fieldOptions(t, req, "testproto/testproto.proto", "ContentOptionsTest", "json_field")
+		options.ContentSchema = `{"type": "object", "required": ["kind"]}`
+	})
+
+	require.Equal(t, map[string]any{
+		"type":             "string",
+		"contentMediaType": "application/json",
+		"contentSchema":    map[string]any{"type": "object", "required": []any{"kind"}},
+	}, lookup(t, document(t, res, "testproto/ContentOptionsTest.schema.json"), "properties", "jsonField"))
+}
+
+// fieldOptions returns the jsonschema options of a field in a request, which are written back when the request is
+// marshaled.
+func fieldOptions(t *testing.T, req *pluginpb.CodeGeneratorRequest, filename, message, field string) *jsonschemapb.FieldOptions {
+	t.Helper()
+
+	for _, file := range req.GetProtoFile() {
+		if file.GetName() != filename {
+			continue
+		}
+
+		for _, m := range file.GetMessageType() {
+			if m.GetName() != message {
+				continue
+			}
+
+			for _, f := range m.GetField() {
+				if f.GetName() == field {
+					options, ok := proto.GetExtension(f.GetOptions(), jsonschemapb.E_Field).(*jsonschemapb.FieldOptions)
+					require.True(t, ok)
+					return options
+				}
+			}
+		}
+	}
+
+	require.Failf(t, "field not found", "%s.%s not found in %s", message, field, filename)
+	return nil
+}
+
+func TestStructSchemaOption(t *testing.T) {
+	doc := document(t, render(t, ""), "testproto/StructSchemaOptionTest.schema.json")
+
+	// The given schema replaces the definition of google.protobuf.Struct, but values must still be objects.
+	require.Equal(t, map[string]any{
+		"type":  "object",
+		"allOf": []any{map[string]any{"additionalProperties": map[string]any{"type": "string"}}},
+	}, lookup(t, doc, "properties", "labels"))
+	require.NotContains(t, doc, "definitions")
+}
+
+func TestAccessOptions(t *testing.T) {
+	properties := lookup(t, document(t, render(t, ""), "testproto/AccessOptionsTest.schema.json"), "properties")
+
+	require.Equal(t, map[string]any{"readOnly": true, "type": "string"}, lookup(t, properties, "id"))
+	require.Equal(t, map[string]any{"writeOnly": true, "type": "string"}, lookup(t, properties, "password"))
+	require.Equal(t, map[string]any{"readOnly": true, "allOf": []any{map[string]any{"$ref": "#"}}}, lookup(t, properties, "parent"))
+
+	output := renderFailure(t, "draft=draft-06,include=testproto.AccessOptionsTest")
+	require.Contains(t, output, "read_only and write_only options require draft draft-07 or later")
+}
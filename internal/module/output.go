@@ -0,0 +1,102 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonpatch"
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+const (
+	defaultExtension = ".schema.json"
+	defaultIndent    = 2
+	minYAMLIndent    = 2
+)
+
+// title returns the title of a schema, or nothing if titles are omitted.
+func (m *Module) title(title string) string {
+	if m.omitTitles {
+		return ""
+	}
+
+	return title
+}
+
+func (m *Module) parseIndent(value string) int {
+	indent, err := strconv.Atoi(value)
+	if err != nil || indent < 0 {
+		m.Failf("invalid value %q for indent parameter (expected a number of spaces, or 0 for compact output)", value)
+	}
+
+	return indent
+}
+
+func (m *Module) parseExtension(value string) string {
+	if !strings.HasPrefix(value, ".") || !(isJSON(value) || isYAML(value)) {
+		m.Failf("invalid value %q for extension parameter (expected an extension ending in .json, .yaml or .yml)", value)
+	}
+
+	return value
+}
+
+// encode renders a schema in the format implied by the extension of the file that it is written to, after applying a
+// JSON patch to it.
+func (m *Module) encode(schema jsonschema.Schema, filename string, patch []jsonpatch.Operation) string {
+	content, err := json.Marshal(schema)
+	m.CheckErr(err, "failed to marshal JSON schema")
+
+	if len(patch) > 0 {
+		content, err = jsonpatch.Apply(content, patch)
+		m.CheckErr(err, "failed to apply JSON patch to "+filename)
+	}
+
+	if !isYAML(filename) {
+		if m.indent == 0 {
+			return string(content) + "\n"
+		}
+
+		var buf bytes.Buffer
+		m.CheckErr(json.Indent(&buf, content, "", strings.Repeat(" ", m.indent)), "failed to indent JSON schema")
+		return buf.String() + "\n"
+	}
+
+	// YAML is a superset of JSON, so decoding the JSON preserves the order of the keys.
+	var node yaml.Node
+	m.CheckErr(yaml.Unmarshal(content, &node), "failed to convert JSON schema to YAML")
+	useBlockStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	// YAML relies on indentation, so it is never compact.
+	encoder.SetIndent(max(m.indent, minYAMLIndent))
+	m.CheckErr(encoder.Encode(&node), "failed to marshal JSON schema as YAML")
+	m.CheckErr(encoder.Close(), "failed to marshal JSON schema as YAML")
+	return buf.String()
+}
+
+// useBlockStyle clears the flow and quoting styles that nodes decoded from JSON have, so that the YAML encoder picks
+// the most readable style for each one.
+func useBlockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		useBlockStyle(child)
+	}
+}
+
+func isJSON(filename string) bool {
+	return path.Ext(filename) == ".json"
+}
+
+func isYAML(filename string) bool {
+	extension := path.Ext(filename)
+	return extension == ".yaml" || extension == ".yml"
+}
@@ -0,0 +1,77 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// outputData is the data that the output template is executed with for each document.
+type outputData struct {
+	// Content is the schema, rendered as it would be written without a template.
+	Content string
+	// ID is the $id of the schema.
+	ID string
+	// Filename is the path of the file that the document is written to.
+	Filename string
+	// Package is the package of the document, such as foo.bar.v1.
+	Package string
+	// PackagePath is the package as a path, such as foo/bar/v1.
+	PackagePath string
+	// Version is the version at the end of the package, such as v1, or empty if the package isn't versioned.
+	Version string
+	// File is the path of the proto file without its extension, such as foo/bar/v1/baz, or empty if the document
+	// is for a package.
+	File string
+	// Message is the name of the message within its package, such as Outer.Inner, or empty if the document is for a
+	// group of messages.
+	Message string
+}
+
+func newOutputData(id, content string, placeholders map[string]string) outputData {
+	return outputData{
+		Content:     content,
+		ID:          id,
+		Filename:    placeholders[placeholderFilename],
+		Package:     placeholders[placeholderPackage],
+		PackagePath: placeholders[placeholderPackagePath],
+		Version:     placeholders[placeholderVersion],
+		File:        placeholders[placeholderFile],
+		Message:     placeholders[placeholderMessage],
+	}
+}
+
+// outputTemplateFuncs are the functions available to output templates, in addition to the builtin ones.
+var outputTemplateFuncs = template.FuncMap{
+	// indent indents every line but the first by a number of spaces, for embedding content in a YAML block scalar.
+	"indent": func(spaces int, content string) string {
+		return strings.ReplaceAll(strings.TrimSuffix(content, "\n"), "\n", "\n"+strings.Repeat(" ", spaces))
+	},
+	// quote encodes a string as a JSON string, which is also a valid YAML scalar.
+	"quote": func(content string) (string, error) {
+		data, err := json.Marshal(content)
+		return string(data), err
+	},
+}
+
+func (m *Module) parseOutputTemplate(path string) *template.Template {
+	data, err := os.ReadFile(path) //nolint:gosec // The path is chosen by whoever runs the plugin.
+	m.CheckErr(err, "failed to read output template")
+
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Funcs(outputTemplateFuncs).Parse(string(data))
+	m.CheckErr(err, "invalid output template")
+	return tmpl
+}
+
+// executeOutputTemplate returns the content of a document, wrapped or transformed by the output template.
+func (m *Module) executeOutputTemplate(data outputData) string {
+	var buf bytes.Buffer
+	m.CheckErr(m.outputTemplate.Execute(&buf, data), "failed to execute output template for "+data.Filename)
+	return buf.String()
+}
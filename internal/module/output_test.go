@@ -0,0 +1,117 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/pluginpb"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExtension(t *testing.T) {
+	want := document(t, render(t, ""), "testproto/RecursiveTest.schema.json")
+	delete(want, "$id")
+
+	testCases := []struct {
+		parameter string
+		filename  string
+	}{
+		{parameter: "extension=.schema.yaml", filename: "testproto/RecursiveTest.schema.yaml"},
+		{parameter: "extension=.yml", filename: "testproto/RecursiveTest.yml"},
+		{parameter: "filename_template={message}.yaml", filename: "RecursiveTest.yaml"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			doc := yamlDocument(t, render(t, tc.parameter), tc.filename)
+			require.Equal(t, "https://protoc-gen-jsonschema.cerbos.dev/"+tc.filename, doc["$id"])
+
+			delete(doc, "$id")
+			require.Equal(t, want, doc)
+		})
+	}
+
+	output := renderFailure(t, "extension=.txt")
+	require.Contains(t, output, `invalid value ".txt" for extension parameter`)
+}
+
+// yamlDocument decodes a document written as YAML from the response.
+func yamlDocument(t *testing.T, res *pluginpb.CodeGeneratorResponse, filename string) map[string]any {
+	t.Helper()
+
+	var doc map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(content(t, res, filename)), &doc), filename)
+	return doc
+}
+
+func TestIndent(t *testing.T) {
+	testCases := []struct {
+		parameter string
+		filename  string
+		prefix    string
+	}{
+		{parameter: "", filename: "testproto/FieldNamesTest.schema.json", prefix: "{\n  \"$id\": "},
+		{parameter: "indent=4", filename: "testproto/FieldNamesTest.schema.json", prefix: "{\n    \"$id\": "},
+		{parameter: "indent=0", filename: "testproto/FieldNamesTest.schema.json", prefix: `{"$id":"https://`},
+		{parameter: "indent=4,extension=.yaml", filename: "testproto/FieldNamesTest.yaml", prefix: "$id: "},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			res := render(t, tc.parameter)
+			require.True(t, strings.HasPrefix(content(t, res, tc.filename), tc.prefix), content(t, res, tc.filename))
+		})
+	}
+
+	require.Contains(t, content(t, render(t, "indent=4,extension=.yaml"), "testproto/FieldNamesTest.yaml"), "\nproperties:\n    snakeCaseField:\n        type: string\n")
+	compact := content(t, render(t, "indent=0"), "testproto/FieldNamesTest.schema.json")
+	require.Equal(t, 1, strings.Count(compact, "\n"))
+	require.True(t, strings.HasSuffix(compact, "}\n"))
+
+	require.Contains(t, renderFailure(t, "indent=-1"), `invalid value "-1" for indent parameter`)
+}
+
+func TestOutputTemplate(t *testing.T) {
+	template := filepath.Join(t.TempDir(), "template.yaml")
+	require.NoError(t, os.WriteFile(template, []byte(`id: {{ quote .ID }}
+names: {{ .Filename }} {{ .Package }} {{ .PackagePath }} {{ .File }} {{ .Message }} [{{ .Version }}]
+schema: |
+  {{ indent 2 .Content }}
+`), 0o600))
+
+	filename := "testproto/other/NameCollisionTest.schema.json"
+	schema := content(t, render(t, ""), filename)
+
+	require.Equal(t, `id: "https://protoc-gen-jsonschema.cerbos.dev/testproto/other/NameCollisionTest.schema.json"
+names: testproto/other/NameCollisionTest.schema.json testproto.other testproto/other testproto/other/other NameCollisionTest []
+schema: |
+  `+strings.ReplaceAll(strings.TrimSuffix(schema, "\n"), "\n", "\n  ")+"\n", content(t, render(t, "output_template="+template), filename))
+}
+
+func TestOmitTitles(t *testing.T) {
+	res := render(t, "omit_titles=true")
+
+	doc := document(t, res, "testproto/RequiredModesTest.schema.json")
+	require.Equal(t, map[string]any{
+		"description":          "A generic empty message.",
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties":           map[string]any{},
+	}, lookup(t, doc, "definitions", "google.protobuf.Empty"))
+
+	doc = document(t, res, "testproto/EmptyByteRulesTest.schema.json")
+	for _, alternative := range lookup(t, doc, "properties", "byteField", "anyOf").([]any) {
+		require.NotContains(t, alternative, "title")
+	}
+
+	// Titles given by options are kept.
+	doc = document(t, res, "testproto/TextOptionTest.schema.json")
+	require.Equal(t, "Text options", doc["title"])
+	require.Equal(t, "Name", lookup(t, doc, "properties", "name", "title"))
+}
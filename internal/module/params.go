@@ -0,0 +1,209 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// parameterKind is the type of value that a parameter takes.
+type parameterKind int
+
+const (
+	parameterString parameterKind = iota
+	// parameterBool is true or false, and is true if the parameter is given without a value.
+	parameterBool
+	// parameterList is a list of values separated by semicolons, which can also be given by repeating the parameter.
+	parameterList
+)
+
+// listSeparator separates the values of list parameters.
+const listSeparator = ";"
+
+// knownParameters are the parameters that the plugin accepts.
+var knownParameters = map[string]parameterKind{
+	"allow_null_values": parameterBool,
+	"anchors":           parameterBool,
+	"base64":            parameterString,
+	"baseurl":           parameterString,
+	"config":            parameterString,
+	"definition_names":  parameterString,
+	"draft":             parameterString,
+	"dynamic_refs":      parameterBool,
+	"enum":              parameterString,
+	"enum_descriptions": parameterBool,
+	"enum_prefix":       parameterString,
+	"exclude":           parameterList,
+	"expand_any":        parameterBool,
+	"extension":         parameterString,
+	"field_names":       parameterString,
+	"filename_template": parameterString,
+	"format_assertion":  parameterBool,
+	"formats":           parameterString,
+	"group":             parameterString,
+	"id_template":       parameterString,
+	"include":           parameterList,
+	"indent":            parameterString,
+	"int64":             parameterString,
+	"log_level":         parameterString,
+	"non_finite_floats": parameterBool,
+	"omit_titles":       parameterBool,
+	"oneof":             parameterString,
+	"only_annotated":    parameterBool,
+	"open_enums":        parameterBool,
+	"optional":          parameterString,
+	"output_path":       parameterString, // Reserved by protoc-gen-star.
+	"output_template":   parameterString,
+	"property_order":    parameterBool,
+	"provenance":        parameterString,
+	"required":          parameterString,
+	"root":              parameterString,
+	"strict":            parameterBool,
+	"top_level_only":    parameterBool,
+	"warnings_report":   parameterString,
+}
+
+// parameters maps the names of the parameters that were given to their values.
+type parameters map[string]string
+
+// parseParameters parses the parameter string that protoc passes to the plugin, which is a comma-separated list of
+// name=value pairs. Commas and backslashes in values are escaped with a backslash.
+func parseParameters(raw string) (parameters, error) {
+	params := make(parameters)
+
+	for _, pair := range splitParameters(raw) {
+		if pair == "" {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(pair, "=")
+		if !hasValue {
+			if kind, ok := knownParameters[name]; ok && kind != parameterBool {
+				return nil, fmt.Errorf("parameter %s requires a value (use %s=<value>)", name, name)
+			}
+
+			value = "true"
+		}
+
+		if err := params.add(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return params, nil
+}
+
+// splitParameters splits the parameter string at commas that aren't escaped, and removes the escaping.
+func splitParameters(raw string) []string {
+	var pairs []string
+	var pair strings.Builder
+
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; {
+		case c == '\\' && i+1 < len(raw) && (raw[i+1] == ',' || raw[i+1] == '\\'):
+			i++
+			pair.WriteByte(raw[i])
+
+		case c == ',':
+			pairs = append(pairs, pair.String())
+			pair.Reset()
+
+		default:
+			pair.WriteByte(c)
+		}
+	}
+
+	return append(pairs, pair.String())
+}
+
+// add checks that a parameter is known and that its value is valid, and sets it. List parameters can be given more
+// than once, in which case the values are combined. Other parameters can only be given once.
+func (p parameters) add(name, value string) error {
+	kind, ok := knownParameters[name]
+	if !ok {
+		if suggestion := closestParameter(name); suggestion != "" {
+			return fmt.Errorf("unknown parameter %q (did you mean %q?)", name, suggestion)
+		}
+
+		return fmt.Errorf("unknown parameter %q (expected one of %q)", name, slices.Sorted(maps.Keys(knownParameters)))
+	}
+
+	if kind == parameterBool {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid value %q for %s parameter (expected true or false)", value, name)
+		}
+	}
+
+	previous, ok := p[name]
+	switch {
+	case !ok:
+		p[name] = value
+
+	case kind == parameterList:
+		p[name] = previous + listSeparator + value
+
+	default:
+		return fmt.Errorf("parameter %s is given more than once", name)
+	}
+
+	return nil
+}
+
+// str returns the value of a parameter, or the default if it wasn't given.
+func (p parameters) str(name, def string) string {
+	if value, ok := p[name]; ok {
+		return value
+	}
+
+	return def
+}
+
+// flag returns the value of a boolean parameter, which is false if it wasn't given.
+func (p parameters) flag(name string) bool {
+	value, _ := strconv.ParseBool(p[name]) // The value was checked when it was added.
+	return value
+}
+
+// closestParameter returns the known parameter that is only a few edits away from a name, if there is one, to suggest
+// when the name is misspelt.
+func closestParameter(name string) string {
+	closest, closestDistance := "", max(1, len(name)/3)+1
+
+	for _, known := range slices.Sorted(maps.Keys(knownParameters)) {
+		if distance := editDistance(name, known); distance < closestDistance {
+			closest, closestDistance = known, distance
+		}
+	}
+
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
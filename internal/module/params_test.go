@@ -0,0 +1,54 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseParameters(t *testing.T) {
+	testCases := []struct {
+		name   string
+		raw    string
+		params parameters
+		err    string
+	}{
+		{name: "empty", raw: "", params: parameters{}},
+		{name: "values", raw: "draft=2020-12,enum=number", params: parameters{"draft": "2020-12", "enum": "number"}},
+		{name: "empty pairs", raw: ",draft=2020-12,,", params: parameters{"draft": "2020-12"}},
+		{name: "flag without value", raw: "strict,property_order=false", params: parameters{"strict": "true", "property_order": "false"}},
+		{name: "escaped comma", raw: `include=/^a\,b$/,strict`, params: parameters{"include": "/^a,b$/", "strict": "true"}},
+		{name: "escaped backslash", raw: `baseurl=a\\,strict`, params: parameters{"baseurl": `a\`, "strict": "true"}},
+		{name: "other backslashes", raw: `include=/\d+/`, params: parameters{"include": `/\d+/`}},
+		{name: "value with equals sign", raw: "id_template=urn:x=y", params: parameters{"id_template": "urn:x=y"}},
+		{name: "repeated list", raw: "include=a.*,exclude=b,include=c.**", params: parameters{"include": "a.*;c.**", "exclude": "b"}},
+		{name: "repeated string", raw: "draft=07,draft=2020-12", err: "parameter draft is given more than once"},
+		{name: "string without value", raw: "draft", err: "parameter draft requires a value (use draft=<value>)"},
+		{name: "invalid flag", raw: "strict=yes", err: `invalid value "yes" for strict parameter (expected true or false)`},
+		{name: "misspelt", raw: "enum_prefx=strip", err: `unknown parameter "enum_prefx" (did you mean "enum_prefix"?)`},
+		{name: "unknown", raw: "colour=blue", err: `unknown parameter "colour" (expected one of [`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params, err := parseParameters(tc.raw)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.params, params)
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	require.Equal(t, 0, editDistance("strict", "strict"))
+	require.Equal(t, 1, editDistance("strict", "strct"))
+	require.Equal(t, 2, editDistance("indent", "intend"))
+	require.Equal(t, 6, editDistance("", "strict"))
+}
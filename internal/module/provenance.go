@@ -0,0 +1,120 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	pgs "github.com/lyft/protoc-gen-star/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
+)
+
+// provenanceMode selects how documents record how they were generated.
+type provenanceMode string
+
+const (
+	// provenanceNone doesn't record how documents were generated.
+	provenanceNone provenanceMode = "none"
+	// provenanceComment records how documents were generated in a $comment, which validators ignore.
+	provenanceComment provenanceMode = "comment"
+	// provenanceExtension records how documents were generated in an x-generated-by object, for tools that read it.
+	provenanceExtension provenanceMode = "extension"
+)
+
+func (m *Module) parseProvenanceMode(value string) provenanceMode {
+	mode := provenanceMode(value)
+	switch mode {
+	case provenanceNone, provenanceComment, provenanceExtension:
+		return mode
+	default:
+		m.Failf("invalid value %q for provenance parameter (expected %q, %q or %q)", value, provenanceNone, provenanceComment, provenanceExtension)
+		return ""
+	}
+}
+
+// provenance describes how a document was generated. It doesn't include the time of generation, so that generating
+// the same documents from the same sources gives the same output.
+type provenance struct {
+	Generator  string   `json:"generator"`
+	Version    string   `json:"version"`
+	Protoc     string   `json:"protoc,omitempty"`
+	Sources    []string `json:"sources"`
+	SourceHash string   `json:"sourceHash"`
+}
+
+// addProvenance records how a document was generated from the proto files that declare its messages.
+func (m *Module) addProvenance(schema jsonschema.NonTrivialSchema, sources []pgs.File) {
+	if m.provenanceMode == provenanceNone {
+		return
+	}
+
+	p := provenance{
+		Generator:  "protoc-gen-jsonschema",
+		Version:    pluginVersion(),
+		Protoc:     formatCompilerVersion(m.compilerVersion),
+		Sources:    make([]string, len(sources)),
+		SourceHash: m.hashSources(sources),
+	}
+
+	for i, source := range sources {
+		p.Sources[i] = source.Name().String()
+	}
+
+	switch m.provenanceMode {
+	case provenanceExtension:
+		schema.SetExtension("x-generated-by", p)
+
+	default:
+		comment := fmt.Sprintf("Generated by %s %s", p.Generator, p.Version)
+		if p.Protoc != "" {
+			comment += " with protoc " + p.Protoc
+		}
+
+		schema.SetComment(fmt.Sprintf("%s from %s (%s)", comment, strings.Join(p.Sources, ", "), p.SourceHash))
+	}
+}
+
+// hashSources returns the SHA-256 digest of the descriptors of the source files, which include their comments, so that
+// it changes whenever the sources of a document do.
+func (m *Module) hashSources(sources []pgs.File) string {
+	hash := sha256.New()
+	for _, source := range sources {
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(source.Descriptor())
+		m.CheckErr(err, "failed to marshal descriptor of "+source.Name().String())
+		hash.Write(data)
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// pluginVersion returns the version of the module that the plugin was built from, which is only known if it was built
+// with go install.
+func pluginVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+
+	return "(devel)"
+}
+
+// formatCompilerVersion formats the version of protoc that sent the request, if it is known.
+func formatCompilerVersion(version *pluginpb.Version) string {
+	if version == nil {
+		return ""
+	}
+
+	formatted := fmt.Sprintf("%d.%d.%d", version.GetMajor(), version.GetMinor(), version.GetPatch())
+	if suffix := version.GetSuffix(); suffix != "" {
+		formatted += "-" + suffix
+	}
+
+	return formatted
+}
@@ -0,0 +1,37 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenance(t *testing.T) {
+	const filename = "testproto/FieldNamesTest.schema.json"
+
+	doc := document(t, render(t, ""), filename)
+	require.NotContains(t, doc, "$comment")
+	require.NotContains(t, doc, "x-generated-by")
+
+	doc = document(t, render(t, "provenance=extension"), filename)
+	require.NotContains(t, doc, "$comment")
+	generatedBy := lookup(t, doc, "x-generated-by")
+	require.Equal(t, "protoc-gen-jsonschema", lookup(t, generatedBy, "generator"))
+	require.NotEmpty(t, lookup(t, generatedBy, "version"))
+	require.Equal(t, []any{"testproto/testproto.proto"}, lookup(t, generatedBy, "sources"))
+	hash, ok := lookup(t, generatedBy, "sourceHash").(string)
+	require.True(t, ok)
+	require.Regexp(t, `^sha256:[0-9a-f]{64}$`, hash)
+
+	// The hash is derived from the sources rather than the time, so the output is reproducible.
+	require.Equal(t, generatedBy, lookup(t, document(t, render(t, "provenance=extension"), filename), "x-generated-by"))
+
+	doc = document(t, render(t, "provenance=comment"), filename)
+	require.NotContains(t, doc, "x-generated-by")
+	comment, ok := lookup(t, doc, "$comment").(string)
+	require.True(t, ok)
+	require.Regexp(t, `^Generated by protoc-gen-jsonschema \S+ from testproto/testproto\.proto \(`+hash+`\)$`, comment)
+}
@@ -0,0 +1,131 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	pgs "github.com/lyft/protoc-gen-star/v2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// requiredMode selects how the generator decides which properties are required.
+type requiredMode string
+
+const (
+	// requiredFromRules requires properties whose protovalidate rules reject a missing value.
+	requiredFromRules requiredMode = "protovalidate"
+	// requiredFromPresence requires singular fields that don't track presence, which protojson can always emit.
+	requiredFromPresence requiredMode = "presence"
+	// requiredFromFieldBehavior requires fields annotated with the google.api.field_behavior REQUIRED option.
+	requiredFromFieldBehavior requiredMode = "field_behavior"
+	// requiredNever doesn't require any properties.
+	requiredNever requiredMode = "none"
+	// requiredAlways requires every field, for documents that are expected to be fully populated.
+	requiredAlways requiredMode = "all"
+)
+
+const (
+	// fieldBehaviorNumber is the field number of the google.api.field_behavior extension to FieldOptions.
+	fieldBehaviorNumber protowire.Number = 1052
+	// fieldBehaviorRequired is the number of the REQUIRED value of the google.api.FieldBehavior enum.
+	fieldBehaviorRequired = 2
+)
+
+// optionalMode selects how fields declared with the proto3 optional keyword are treated, independently of how the
+// generator decides which other properties are required.
+type optionalMode string
+
+const (
+	// optionalNotRequired never requires optional fields.
+	optionalNotRequired optionalMode = "not_required"
+	// optionalFromRequiredMode requires optional fields on the same basis as other fields.
+	optionalFromRequiredMode optionalMode = "required_mode"
+	// optionalNullable never requires optional fields, and also accepts null, which protojson treats as unset.
+	optionalNullable optionalMode = "nullable"
+)
+
+func (m *Module) parseOptionalMode(value string) optionalMode {
+	mode := optionalMode(value)
+	switch mode {
+	case optionalNotRequired, optionalFromRequiredMode, optionalNullable:
+		return mode
+	default:
+		m.Failf("invalid value %q for optional parameter (expected %q, %q or %q)", value, optionalNotRequired, optionalFromRequiredMode, optionalNullable)
+		return ""
+	}
+}
+
+func (m *Module) parseRequiredMode(value string) requiredMode {
+	mode := requiredMode(value)
+	switch mode {
+	case requiredFromRules, requiredFromPresence, requiredFromFieldBehavior, requiredNever, requiredAlways:
+		return mode
+	default:
+		m.Failf("invalid value %q for required parameter (expected %q, %q, %q, %q or %q)", value, requiredFromRules, requiredFromPresence, requiredFromFieldBehavior, requiredNever, requiredAlways)
+		return ""
+	}
+}
+
+func (m *Module) fieldRequired(field pgs.Field, rules *validate.FieldRules) bool {
+	m.debug("fieldRequired")
+	if m.fieldOptions(field).GetRequired() {
+		return true
+	}
+
+	// The optional parameter doesn't override a rule that explicitly requires the field.
+	if m.fieldHasOptionalKeyword(field) && m.optionalMode != optionalFromRequiredMode && !rules.GetRequired() {
+		return false
+	}
+
+	mode := m.requiredMode
+	if field.Message() != nil {
+		if override := m.override(field.Message()).Required; override != "" {
+			mode = override
+		}
+	}
+
+	// protobuf itself rejects messages that are missing proto2 required fields.
+	if m.fieldRequiredByProtobuf(field) && mode != requiredNever {
+		return true
+	}
+
+	switch mode {
+	case requiredFromPresence:
+		return !(m.fieldHasPresence(field) || field.Type().IsRepeated() || field.Type().IsMap())
+
+	case requiredFromFieldBehavior:
+		return m.fieldBehaviorRequired(field)
+
+	case requiredNever:
+		return false
+
+	case requiredAlways:
+		return true
+
+	default:
+		return m.fieldRequiredByRules(field, rules)
+	}
+}
+
+func (m *Module) fieldRequiredByRules(field pgs.Field, rules *validate.FieldRules) bool {
+	m.debug("fieldRequiredByRules")
+	if rules.GetIgnore() == validate.Ignore_IGNORE_IF_ZERO_VALUE {
+		return false
+	}
+
+	// Fields without presence are always validated, so a container that must not be empty has to be present.
+	return rules.GetRequired() || rules.GetRepeated().GetMinItems() > 0 || rules.GetMap().GetMinPairs() > 0
+}
+
+// fieldBehaviorRequired reads the google.api.field_behavior option from the unknown fields of the field options.
+func (m *Module) fieldBehaviorRequired(field pgs.Field) bool {
+	m.debug("fieldBehaviorRequired")
+	for _, behavior := range m.unknownVarints(field.Descriptor().GetOptions().ProtoReflect(), fieldBehaviorNumber) {
+		if behavior == fieldBehaviorRequired {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,99 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredModes(t *testing.T) {
+	testCases := []struct {
+		parameter string
+		required  []any
+	}{
+		{parameter: "", required: []any{"ruleField"}},
+		{parameter: "required=protovalidate", required: []any{"ruleField"}},
+		{parameter: "required=presence", required: []any{"ruleField", "plainField", "behaviorField"}},
+		{parameter: "required=field_behavior", required: []any{"behaviorField"}},
+		{parameter: "required=none"},
+		{parameter: "required=all", required: []any{"ruleField", "plainField", "repeatedField", "messageField", "behaviorField"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			doc := document(t, render(t, tc.parameter), "testproto/RequiredModesTest.schema.json")
+			if tc.required == nil {
+				require.NotContains(t, doc, "required")
+				return
+			}
+
+			// Members of oneofs are never required individually, even if their rules require them.
+			require.Equal(t, tc.required, lookup(t, doc, "required"))
+		})
+	}
+}
+
+func TestOptionalModes(t *testing.T) {
+	testCases := []struct {
+		parameter string
+		required  []any
+		schema    map[string]any
+	}{
+		{
+			parameter: "required=field_behavior",
+			required:  []any{"behaviorField"},
+			schema:    map[string]any{"type": "string"},
+		},
+		{
+			parameter: "required=field_behavior,optional=not_required",
+			required:  []any{"behaviorField"},
+			schema:    map[string]any{"type": "string"},
+		},
+		{
+			parameter: "required=field_behavior,optional=required_mode",
+			required:  []any{"behaviorField", "optionalBehaviorField"},
+			schema:    map[string]any{"type": "string"},
+		},
+		{
+			parameter: "required=all,optional=required_mode",
+			required:  []any{"ruleField", "plainField", "optionalField", "repeatedField", "messageField", "behaviorField", "optionalBehaviorField"},
+			schema:    map[string]any{"type": "string"},
+		},
+		{
+			parameter: "required=field_behavior,optional=nullable",
+			required:  []any{"behaviorField"},
+			schema:    map[string]any{"anyOf": []any{map[string]any{"type": "string"}, map[string]any{"type": "null"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			doc := document(t, render(t, tc.parameter), "testproto/RequiredModesTest.schema.json")
+			require.Equal(t, tc.required, lookup(t, doc, "required"))
+			require.Equal(t, tc.schema, lookup(t, doc, "properties", "optionalBehaviorField"))
+		})
+	}
+}
+
+func TestEditionsRequired(t *testing.T) {
+	// Fields have explicit presence by default in editions, which mustn't stop their rules from requiring them.
+	for _, optional := range []string{"not_required", "required_mode", "nullable"} {
+		t.Run(optional, func(t *testing.T) {
+			doc := document(t, render(t, "optional="+optional), "testproto/EditionsTest.schema.json")
+			require.ElementsMatch(t, []any{"explicitField", "implicitField", "legacyRequiredField", "requiredMessageField"}, lookup(t, doc, "required"))
+		})
+	}
+}
+
+func TestEditionsNullable(t *testing.T) {
+	// Only scalar fields that ask for explicit presence themselves are treated like proto3 optional fields.
+	doc := document(t, render(t, "optional=nullable"), "testproto/EditionsTest.schema.json")
+
+	require.Equal(t, map[string]any{"anyOf": []any{map[string]any{"type": "integer"}, map[string]any{"type": "null"}}}, lookup(t, doc, "properties", "optionalField"))
+	require.Equal(t, map[string]any{"type": "string"}, lookup(t, doc, "properties", "explicitField"))
+	require.Equal(t, map[string]any{"$ref": "#/definitions/testproto.EditionsNested"}, lookup(t, doc, "properties", "messageField"))
+	require.Equal(t, map[string]any{"$ref": "#/definitions/testproto.EditionsNested"}, lookup(t, doc, "properties", "requiredMessageField"))
+}
@@ -4,11 +4,14 @@
 package module
 
 import (
+	"encoding/base64"
+	"fmt"
 	"io"
 	"regexp"
 	"regexp/syntax"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	pgs "github.com/lyft/protoc-gen-star/v2"
@@ -16,8 +19,47 @@ import (
 	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
 )
 
+const (
+	ipv4Length = 4
+	ipv6Length = 16
+)
+
+// Patterns used by protovalidate for the well_known_regex rule.
+const (
+	httpHeaderNameStrict  = `^:?[0-9a-zA-Z!#$%&'*+-.^_|~\x60]+$`
+	httpHeaderValueStrict = `^[^\u0000-\u0008\u000A-\u001F\u007F]*$`
+	httpHeaderLoose       = `^[^\u0000\u000A\u000D]*$`
+)
+
+const (
+	// uuidPattern backs up the "uuid" format, which draft-07 doesn't define.
+	uuidPattern  = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+	tuuidPattern = `^[0-9a-fA-F]{32}$`
+)
+
+// These patterns check the overall shape of addresses and ports. They can't check that IPv6 addresses are well-formed or
+// that the host bits of a prefix are zero, so they accept some values that protovalidate rejects.
+const (
+	ipv4Octet          = `(?:25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])`
+	ipv4Address        = `(?:` + ipv4Octet + `\.){3}` + ipv4Octet
+	ipv6Address        = `[0-9A-Fa-f:.]*:[0-9A-Fa-f:.]*`
+	ipv4PrefixLength   = `(?:3[0-2]|[12]?[0-9])`
+	ipv6PrefixLength   = `(?:12[0-8]|1[01][0-9]|[1-9]?[0-9])`
+	ipv4WithPrefixLen  = `^` + ipv4Address + `/` + ipv4PrefixLength + `$`
+	ipv6WithPrefixLen  = `^` + ipv6Address + `/` + ipv6PrefixLength + `$`
+	port               = `(?:6553[0-5]|655[0-2][0-9]|65[0-4][0-9]{2}|6[0-4][0-9]{3}|[1-5][0-9]{4}|[1-9][0-9]{0,3}|0)`
+	hostAndPortPattern = `^(?:[0-9A-Za-z.-]+|\[` + ipv6Address + `\]):` + port + `$`
+)
+
+var base64Encodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.RawStdEncoding,
+	base64.URLEncoding,
+	base64.RawURLEncoding,
+}
+
 func (m *Module) schemaForScalar(scalar pgs.ProtoType, rules *validate.FieldRules) jsonschema.Schema {
-	m.Debug("schemaForScalar")
+	m.debug("schemaForScalar")
 	if scalar.IsNumeric() {
 		return m.schemaForNumericScalar(scalar, rules)
 	}
@@ -26,7 +68,7 @@ func (m *Module) schemaForScalar(scalar pgs.ProtoType, rules *validate.FieldRule
 	case pgs.BoolT:
 		return m.schemaForBool(rules.GetBool())
 	case pgs.BytesT:
-		return m.schemaForBytes()
+		return m.schemaForBytes(rules.GetBytes())
 	case pgs.StringT:
 		return m.schemaForString(rules.GetString())
 	default:
@@ -36,7 +78,7 @@ func (m *Module) schemaForScalar(scalar pgs.ProtoType, rules *validate.FieldRule
 }
 
 func (m *Module) schemaForBool(rules *validate.BoolRules) jsonschema.Schema {
-	m.Debug("schemaForBool")
+	m.debug("schemaForBool")
 	schema := jsonschema.NewBooleanSchema()
 
 	if rules != nil {
@@ -48,24 +90,188 @@ func (m *Module) schemaForBool(rules *validate.BoolRules) jsonschema.Schema {
 	return schema
 }
 
-func (m *Module) schemaForBytes() jsonschema.Schema {
-	m.Debug("schemaForBytes")
+// base64Mode selects which base64 alphabets are accepted for bytes.
+type base64Mode string
+
+const (
+	// base64StandardOrURLSafe accepts either alphabet, as protojson does when parsing.
+	base64StandardOrURLSafe base64Mode = "both"
+	// base64Standard only accepts the standard alphabet, which is how protojson encodes bytes.
+	base64Standard base64Mode = "standard"
+	// base64URLSafe only accepts the URL-safe alphabet.
+	base64URLSafe base64Mode = "url"
+)
+
+func (m *Module) parseBase64Mode(value string) base64Mode {
+	mode := base64Mode(value)
+	switch mode {
+	case base64StandardOrURLSafe, base64Standard, base64URLSafe:
+		return mode
+	default:
+		m.Failf("invalid value %q for base64 parameter (expected %q, %q or %q)", value, base64StandardOrURLSafe, base64Standard, base64URLSafe)
+		return ""
+	}
+}
+
+func (m *Module) schemaForBytes(rules *validate.BytesRules) jsonschema.Schema {
+	m.debug("schemaForBytes")
+
+	standard := jsonschema.NewStringSchema()
+	standard.Title = m.title("Standard base64 encoding")
+	standard.Pattern = `^[\r\nA-Za-z0-9+/]*={0,2}$`
+
+	urlSafe := jsonschema.NewStringSchema()
+	urlSafe.Title = m.title("URL-safe base64 encoding")
+	urlSafe.Pattern = `^[\r\nA-Za-z0-9_-]*={0,2}$`
+
+	schema := jsonschema.NewStringSchema()
+	switch m.base64Mode {
+	case base64Standard:
+		schema.Pattern = standard.Pattern
+	case base64URLSafe:
+		schema.Pattern = urlSafe.Pattern
+	default:
+		schema.AnyOf = []jsonschema.NonTrivialSchema{standard, urlSafe}
+	}
+
+	if m.draft.ContentKeywords() {
+		schema.ContentEncoding = "base64"
+		if m.base64Mode == base64URLSafe {
+			schema.ContentEncoding = "base64url"
+		}
+	}
+
+	schemas := []jsonschema.NonTrivialSchema{schema}
+
+	//nolint:nestif
+	if rules != nil {
+		if rules.Len != nil {
+			schema.MinLength = jsonschema.Size(base64MinLength(rules.GetLen()))
+			schema.MaxLength = jsonschema.Size(base64MaxLength(rules.GetLen()))
+		}
+
+		if rules.MaxLen != nil {
+			schema.MaxLength = jsonschema.Size(base64MaxLength(rules.GetMaxLen()))
+		}
+
+		if rules.MinLen != nil {
+			schema.MinLength = jsonschema.Size(base64MinLength(rules.GetMinLen()))
+		}
+
+		if rules.Const != nil {
+			schemas = append(schemas, m.schemaForBytesIn([][]byte{rules.GetConst()}))
+		}
+
+		if len(rules.In) > 0 {
+			schemas = append(schemas, m.schemaForBytesIn(rules.GetIn()))
+		}
+
+		if len(rules.NotIn) > 0 {
+			schemas = append(schemas, jsonschema.Not(m.schemaForBytesIn(rules.GetNotIn())))
+		}
+
+		if rules.Prefix != nil {
+			schemas = append(schemas, m.schemaForBytesPrefix(schema, rules.GetPrefix())...)
+		}
+
+		if rules.Suffix != nil {
+			schema.SetExtension("x-suffix", base64.StdEncoding.EncodeToString(rules.GetSuffix()))
+		}
+
+		if rules.Contains != nil {
+			schema.SetExtension("x-contains", base64.StdEncoding.EncodeToString(rules.GetContains()))
+		}
+
+		if rules.Pattern != nil {
+			schema.SetExtension("x-pattern", rules.GetPattern())
+		}
+
+		if rules.WellKnown != nil {
+			switch rules.WellKnown.(type) {
+			case *validate.BytesRules_Ip:
+				schemas = append(schemas, jsonschema.AnyOf(m.schemaForBytesOfLength(ipv4Length), m.schemaForBytesOfLength(ipv6Length)))
+
+			case *validate.BytesRules_Ipv4:
+				schemas = append(schemas, m.schemaForBytesOfLength(ipv4Length))
+
+			case *validate.BytesRules_Ipv6:
+				schemas = append(schemas, m.schemaForBytesOfLength(ipv6Length))
+			}
+		}
+	}
+
+	return jsonschema.AllOf(schemas...)
+}
+
+// schemaForBytesIn matches any of the base64 encodings accepted by protojson for the given values.
+func (m *Module) schemaForBytesIn(values [][]byte) *jsonschema.StringSchema {
+	m.debug("schemaForBytesIn")
+	schema := jsonschema.NewStringSchema()
+	seen := make(map[string]struct{})
+
+	for _, value := range values {
+		for _, encoding := range base64Encodings {
+			encoded := encoding.EncodeToString(value)
+			if _, ok := seen[encoded]; !ok {
+				seen[encoded] = struct{}{}
+				schema.Enum = append(schema.Enum, encoded)
+			}
+		}
+	}
+
+	return schema
+}
+
+// schemaForBytesPrefix matches the base64 encoding of the longest part of the prefix that is a multiple of three bytes
+// long, since only that part maps onto whole base64 characters. If some bytes can't be checked, the full prefix is
+// recorded as an annotation instead.
+func (m *Module) schemaForBytesPrefix(schema *jsonschema.StringSchema, prefix []byte) []jsonschema.NonTrivialSchema {
+	m.debug("schemaForBytesPrefix")
+	aligned := prefix[:len(prefix)-len(prefix)%3]
+	if len(aligned) < len(prefix) {
+		schema.SetExtension("x-prefix", base64.StdEncoding.EncodeToString(prefix))
+	}
+
+	if len(aligned) == 0 {
+		return nil
+	}
 
 	standard := jsonschema.NewStringSchema()
-	standard.Title = "Standard base64 encoding"
-	standard.Pattern = `^[\r\nA-Za-z0-9+/]*$`
+	standard.Pattern = "^" + regexp.QuoteMeta(base64.RawStdEncoding.EncodeToString(aligned))
 
 	urlSafe := jsonschema.NewStringSchema()
-	urlSafe.Title = "URL-safe base64 encoding"
-	urlSafe.Pattern = `^[\r\nA-Za-z0-9_-]*$`
+	urlSafe.Pattern = "^" + regexp.QuoteMeta(base64.RawURLEncoding.EncodeToString(aligned))
+
+	if standard.Pattern == urlSafe.Pattern {
+		return []jsonschema.NonTrivialSchema{standard}
+	}
+
+	return []jsonschema.NonTrivialSchema{jsonschema.AnyOf(standard, urlSafe)}
+}
+
+// schemaForBytesOfLength matches the base64 encoding of exactly n bytes, with or without padding.
+func (m *Module) schemaForBytesOfLength(n uint64) *jsonschema.StringSchema {
+	m.debug("schemaForBytesOfLength")
+	unpadded := base64MinLength(n)
+	padding := base64MaxLength(n) - unpadded
 
 	schema := jsonschema.NewStringSchema()
-	schema.OneOf = []jsonschema.NonTrivialSchema{standard, urlSafe}
+	schema.Pattern = fmt.Sprintf(`^[A-Za-z0-9+/_-]{%d}(?:={%d})?$`, unpadded, padding)
 	return schema
 }
 
+// base64MinLength is the length of the shortest (unpadded) base64 encoding of n bytes.
+func base64MinLength(n uint64) uint64 {
+	return (4*n + 2) / 3
+}
+
+// base64MaxLength is the length of the longest (padded) base64 encoding of n bytes, ignoring line breaks.
+func base64MaxLength(n uint64) uint64 {
+	return 4 * ((n + 2) / 3)
+}
+
 func (m *Module) schemaForString(rules *validate.StringRules) jsonschema.Schema {
-	m.Debug("schemaForString")
+	m.debug("schemaForString")
 	schema := jsonschema.NewStringSchema()
 	schemas := []jsonschema.NonTrivialSchema{schema}
 	var patterns []string
@@ -97,6 +303,19 @@ func (m *Module) schemaForString(rules *validate.StringRules) jsonschema.Schema
 			schema.MinLength = jsonschema.Size(rules.GetMinLen())
 		}
 
+		if rules.LenBytes != nil {
+			raiseMinLength(schema, utf8MinLength(rules.GetLenBytes()))
+			lowerMaxLength(schema, rules.GetLenBytes())
+		}
+
+		if rules.MaxBytes != nil {
+			lowerMaxLength(schema, rules.GetMaxBytes())
+		}
+
+		if rules.MinBytes != nil {
+			raiseMinLength(schema, utf8MinLength(rules.GetMinBytes()))
+		}
+
 		if rules.NotContains != nil {
 			contains := jsonschema.NewStringSchema()
 			contains.Pattern = regexp.QuoteMeta(rules.GetNotContains())
@@ -127,25 +346,51 @@ func (m *Module) schemaForString(rules *validate.StringRules) jsonschema.Schema
 				schemas = append(schemas, m.schemaForStringFormats(jsonschema.StringFormatHostname, jsonschema.StringFormatIPv4, jsonschema.StringFormatIPv6))
 
 			case *validate.StringRules_Email:
-				schema.Format = jsonschema.StringFormatEmail
+				schemas = m.applyFormat(schema, jsonschema.StringFormatEmail, schemas)
 
 			case *validate.StringRules_Hostname:
-				schema.Format = jsonschema.StringFormatHostname
+				schemas = m.applyFormat(schema, jsonschema.StringFormatHostname, schemas)
 
 			case *validate.StringRules_Ip:
 				schemas = append(schemas, m.schemaForStringFormats(jsonschema.StringFormatIPv4, jsonschema.StringFormatIPv6))
 
 			case *validate.StringRules_Ipv4:
-				schema.Format = jsonschema.StringFormatIPv4
+				schemas = m.applyFormat(schema, jsonschema.StringFormatIPv4, schemas)
 
 			case *validate.StringRules_Ipv6:
-				schema.Format = jsonschema.StringFormatIPv6
+				schemas = m.applyFormat(schema, jsonschema.StringFormatIPv6, schemas)
 
 			case *validate.StringRules_Uri:
-				schema.Format = jsonschema.StringFormatURI
+				schemas = m.applyFormat(schema, jsonschema.StringFormatURI, schemas)
 
 			case *validate.StringRules_UriRef:
-				schema.Format = jsonschema.StringFormatURIReference
+				schemas = m.applyFormat(schema, jsonschema.StringFormatURIReference, schemas)
+
+			case *validate.StringRules_Uuid:
+				schemas = m.applyFormat(schema, jsonschema.StringFormatUUID, schemas)
+				patterns = append(patterns, uuidPattern)
+
+			case *validate.StringRules_Tuuid:
+				patterns = append(patterns, tuuidPattern)
+
+			case *validate.StringRules_IpWithPrefixlen, *validate.StringRules_IpPrefix:
+				schemas = append(schemas, m.schemaForStringPatterns(ipv4WithPrefixLen, ipv6WithPrefixLen))
+
+			case *validate.StringRules_Ipv4WithPrefixlen, *validate.StringRules_Ipv4Prefix:
+				patterns = append(patterns, ipv4WithPrefixLen)
+
+			case *validate.StringRules_Ipv6WithPrefixlen, *validate.StringRules_Ipv6Prefix:
+				patterns = append(patterns, ipv6WithPrefixLen)
+
+			case *validate.StringRules_HostAndPort:
+				patterns = append(patterns, hostAndPortPattern)
+
+			case *validate.StringRules_WellKnownRegex:
+				// protovalidate only applies the strict rule to HTTP header validation.
+				strict := rules.Strict == nil || rules.GetStrict()
+				if pattern := m.wellKnownRegex(rules.GetWellKnownRegex(), strict); pattern != "" {
+					patterns = append(patterns, pattern)
+				}
 			}
 		}
 	}
@@ -163,13 +408,62 @@ func (m *Module) schemaForString(rules *validate.StringRules) jsonschema.Schema
 	return jsonschema.AllOf(schemas...)
 }
 
+// utf8MinLength is the fewest characters that can take up n bytes when encoded as UTF-8. JSON Schema measures strings
+// in characters, so byte length rules are approximated by assuming the worst case of four bytes per character for
+// lower bounds and the best case of one byte per character for upper bounds.
+func utf8MinLength(n uint64) uint64 {
+	return (n + utf8.UTFMax - 1) / utf8.UTFMax
+}
+
+func raiseMinLength(schema *jsonschema.StringSchema, n uint64) {
+	if schema.MinLength == nil || *schema.MinLength < n {
+		schema.MinLength = jsonschema.Size(n)
+	}
+}
+
+func lowerMaxLength(schema *jsonschema.StringSchema, n uint64) {
+	if schema.MaxLength == nil || *schema.MaxLength > n {
+		schema.MaxLength = jsonschema.Size(n)
+	}
+}
+
+func (m *Module) wellKnownRegex(regex validate.KnownRegex, strict bool) string {
+	m.debug("wellKnownRegex")
+	if !strict {
+		return httpHeaderLoose
+	}
+
+	switch regex {
+	case validate.KnownRegex_KNOWN_REGEX_HTTP_HEADER_NAME:
+		return httpHeaderNameStrict
+
+	case validate.KnownRegex_KNOWN_REGEX_HTTP_HEADER_VALUE:
+		return httpHeaderValueStrict
+
+	default:
+		return ""
+	}
+}
+
 func (m *Module) schemaForStringFormats(formats ...jsonschema.StringFormat) jsonschema.NonTrivialSchema {
-	m.Debug("schemaForStringFormats")
+	m.debug("schemaForStringFormats")
 	schemas := make([]jsonschema.NonTrivialSchema, len(formats))
 
 	for i, format := range formats {
 		schema := jsonschema.NewStringSchema()
-		schema.Format = format
+		schemas[i] = jsonschema.AllOf(m.applyFormat(schema, format, []jsonschema.NonTrivialSchema{schema})...)
+	}
+
+	return jsonschema.AnyOf(schemas...)
+}
+
+func (m *Module) schemaForStringPatterns(patterns ...string) jsonschema.NonTrivialSchema {
+	m.debug("schemaForStringPatterns")
+	schemas := make([]jsonschema.NonTrivialSchema, len(patterns))
+
+	for i, pattern := range patterns {
+		schema := jsonschema.NewStringSchema()
+		schema.Pattern = pattern
 		schemas[i] = schema
 	}
 
@@ -177,7 +471,7 @@ func (m *Module) schemaForStringFormats(formats ...jsonschema.StringFormat) json
 }
 
 func (m *Module) makeRegexpCompatibleWithECMAScript(pattern string) string {
-	m.Debug("makeRegexpCompatibleWithECMAScript")
+	m.debug("makeRegexpCompatibleWithECMAScript")
 	expression, err := syntax.Parse(pattern, syntax.Perl)
 	m.CheckErr(err, "failed to parse regular expression")
 
@@ -0,0 +1,58 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringWellKnownRulesIgnoreStrict(t *testing.T) {
+	// protovalidate only applies strict to HTTP header rules, so loose_email_field is still checked as an email.
+	doc := document(t, render(t, ""), "testproto/StringRulesTest.schema.json")
+
+	require.Equal(t, map[string]any{"type": "string", "format": "email"}, lookup(t, doc, "properties", "looseEmailField"))
+	require.Equal(t, `^[^\u0000\u000A\u000D]*$`, lookup(t, doc, "properties", "headerValueField", "pattern"))
+}
+
+func TestBase64(t *testing.T) {
+	standard := `^[\r\nA-Za-z0-9+/]*={0,2}$`
+	urlSafe := `^[\r\nA-Za-z0-9_-]*={0,2}$`
+
+	testCases := []struct {
+		parameter string
+		schema    map[string]any
+	}{
+		{
+			parameter: "",
+			schema: map[string]any{
+				"type": "string",
+				"anyOf": []any{
+					map[string]any{"title": "Standard base64 encoding", "type": "string", "pattern": standard},
+					map[string]any{"title": "URL-safe base64 encoding", "type": "string", "pattern": urlSafe},
+				},
+				"contentEncoding": "base64",
+			},
+		},
+		{
+			parameter: "base64=standard",
+			schema:    map[string]any{"type": "string", "pattern": standard, "contentEncoding": "base64"},
+		},
+		{
+			parameter: "base64=url",
+			schema:    map[string]any{"type": "string", "pattern": urlSafe, "contentEncoding": "base64url"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.parameter, func(t *testing.T) {
+			doc := document(t, render(t, tc.parameter), "testproto/EmptyByteRulesTest.schema.json")
+			require.Equal(t, tc.schema, lookup(t, doc, "properties", "byteField"))
+		})
+	}
+
+	requireMatches(t, standard, []string{"", "AQID", "+/+/", "AQ=="}, []string{"-_-_", "AQ==="})
+	requireMatches(t, urlSafe, []string{"", "AQID", "-_-_", "AQ=="}, []string{"+/+/", "AQ==="})
+}
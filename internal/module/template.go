@@ -0,0 +1,87 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	pgs "github.com/lyft/protoc-gen-star/v2"
+)
+
+// Placeholders that can appear in templates naming the schema of a message.
+const (
+	// placeholderPackage is replaced with the package of the message, such as foo.bar.v1.
+	placeholderPackage = "{package}"
+	// placeholderPackagePath is replaced with the package of the message as a path, such as foo/bar/v1.
+	placeholderPackagePath = "{package_path}"
+	// placeholderMessage is replaced with the name of the message within its package, such as Outer.Inner.
+	placeholderMessage = "{message}"
+	// placeholderVersion is replaced with the version at the end of the package, such as v1, or nothing if the package
+	// isn't versioned.
+	placeholderVersion = "{version}"
+	// placeholderFile is replaced with the path of the proto file without its extension, such as foo/bar/v1/baz.
+	placeholderFile = "{file}"
+	// placeholderFilename is replaced with the path of the file that the schema is written to.
+	placeholderFilename = "{filename}"
+)
+
+var (
+	// packageVersion matches the last component of a versioned package, as recommended by the buf style guide.
+	packageVersion = regexp.MustCompile(`^v\d+(?:(?:alpha|beta|test)\d*)?$`)
+	// placeholder matches anything that looks like a placeholder.
+	placeholder = regexp.MustCompile(`\{[^{}]*\}`)
+)
+
+// messagePlaceholders returns the replacements for the placeholders that name a message.
+func messagePlaceholders(message pgs.Message) map[string]string {
+	pkg := message.Package().ProtoName().String()
+	name := strings.TrimPrefix(message.FullyQualifiedName(), ".")
+	if pkg != "" {
+		name = strings.TrimPrefix(name, pkg+".")
+	}
+
+	placeholders := filePlaceholders(message.File())
+	placeholders[placeholderMessage] = name
+	return placeholders
+}
+
+// filePlaceholders returns the replacements for the placeholders that name a file and its package.
+func filePlaceholders(file pgs.File) map[string]string {
+	name := file.Name().String()
+	placeholders := packagePlaceholders(file.Package().ProtoName().String())
+	placeholders[placeholderFile] = strings.TrimSuffix(name, path.Ext(name))
+	return placeholders
+}
+
+// packagePlaceholders returns the replacements for the placeholders that name a package.
+func packagePlaceholders(pkg string) map[string]string {
+	version := pkg[strings.LastIndex(pkg, ".")+1:]
+	if !packageVersion.MatchString(version) {
+		version = ""
+	}
+
+	return map[string]string{
+		placeholderPackage:     pkg,
+		placeholderPackagePath: strings.ReplaceAll(pkg, ".", "/"),
+		placeholderVersion:     version,
+	}
+}
+
+// expandTemplate replaces the placeholders in a template, which is described by where it was given, such as
+// "id_template parameter".
+func (m *Module) expandTemplate(source, template string, placeholders map[string]string) string {
+	for _, p := range placeholder.FindAllString(template, -1) {
+		if _, ok := placeholders[p]; !ok {
+			m.Failf("unknown placeholder %s in %s (expected one of %q)", p, source, slices.Sorted(maps.Keys(placeholders)))
+		}
+	}
+
+	return placeholder.ReplaceAllStringFunc(template, func(p string) string {
+		return placeholders[p]
+	})
+}
@@ -0,0 +1,51 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// unknownVarints returns the values of the given field from the unknown fields of a message, whether or not they are
+// packed. It's used to read options whose Go types aren't linked into the generator.
+func (m *Module) unknownVarints(message protoreflect.Message, number protowire.Number) []uint64 {
+	m.debug("unknownVarints")
+	var values []uint64
+	unknown := message.GetUnknown()
+
+	for len(unknown) > 0 {
+		fieldNumber, wireType, n := protowire.ConsumeTag(unknown)
+		m.CheckErr(protowire.ParseError(n), "unable to parse unknown fields")
+		unknown = unknown[n:]
+
+		n = protowire.ConsumeFieldValue(fieldNumber, wireType, unknown)
+		m.CheckErr(protowire.ParseError(n), "unable to parse unknown fields")
+		value := unknown[:n]
+		unknown = unknown[n:]
+
+		if fieldNumber != number {
+			continue
+		}
+
+		switch wireType {
+		case protowire.VarintType:
+			v, _ := protowire.ConsumeVarint(value)
+			values = append(values, v)
+
+		case protowire.BytesType:
+			packed, _ := protowire.ConsumeBytes(value)
+			for len(packed) > 0 {
+				v, n := protowire.ConsumeVarint(packed)
+				m.CheckErr(protowire.ParseError(n), "unable to parse packed unknown field")
+				values = append(values, v)
+				packed = packed[n:]
+			}
+
+		default:
+		}
+	}
+
+	return values
+}
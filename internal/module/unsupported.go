@@ -0,0 +1,218 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	pgs "github.com/lyft/protoc-gen-star/v2"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Reasons that rules can't be expressed in JSON Schema.
+const (
+	reasonAnnotated  = "it is only recorded as an annotation"
+	reasonCEL        = "CEL expressions can't be translated"
+	reasonPredefined = "predefined rules are CEL expressions"
+	reasonOneOf      = "oneof rules of messages aren't supported"
+	reasonByteLength = "JSON Schema measures strings in characters, so it is only approximated"
+)
+
+// unsupportedRule is a validation rule that the generator can't express in JSON Schema, so it is left out of the schema
+// or only recorded as an annotation.
+type unsupportedRule struct {
+	File    string `json:"file"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+	Rule    string `json:"rule"`
+	Reason  string `json:"reason"`
+}
+
+// reportUnsupported fails in strict mode. Otherwise, it logs a warning and records the rule for the warnings report.
+// Messages are defined once per document that references them, so the same rule can be reported more than once.
+func (m *Module) reportUnsupported(rule unsupportedRule) {
+	if m.strict {
+		m.Failf("rule %s can't be expressed in JSON Schema (%s); set strict=false to generate the schema regardless", rule.Rule, rule.Reason)
+	}
+
+	if _, ok := m.unsupportedRules[rule]; ok {
+		return
+	}
+
+	if m.unsupportedRules == nil {
+		m.unsupportedRules = make(map[unsupportedRule]struct{})
+	}
+
+	m.unsupportedRules[rule] = struct{}{}
+	m.warn("rule can't be expressed in JSON Schema", "rule", rule.Rule, "reason", rule.Reason)
+}
+
+// checkMessageRules reports the rules of a message that the generator can't express.
+func (m *Module) checkMessageRules(message pgs.Message) {
+	rules := &validate.MessageRules{}
+	_, err := message.Extension(validate.E_Message, rules)
+	m.CheckErr(err, "unable to read validation rules from message")
+
+	report := func(rule, reason string) {
+		m.reportUnsupported(unsupportedRule{
+			File:    message.File().Name().String(),
+			Message: strings.TrimPrefix(message.FullyQualifiedName(), "."),
+			Rule:    rule,
+			Reason:  reason,
+		})
+	}
+
+	if len(rules.GetCel()) > 0 {
+		report("cel", reasonCEL)
+	}
+
+	if len(rules.GetOneof()) > 0 {
+		report("oneof", reasonOneOf)
+	}
+}
+
+// checkFieldRules reports the rules of a field that the generator can't express, including those of the items of a
+// repeated field and the keys and values of a map.
+func (m *Module) checkFieldRules(field pgs.Field, rules *validate.FieldRules, prefix string) {
+	if rules == nil || rules.GetIgnore() == validate.Ignore_IGNORE_ALWAYS {
+		return
+	}
+
+	// Extensions don't belong to a message of their own, so they're reported under the message they extend, by their
+	// fully-qualified names.
+	message, name := field.Message(), field.Name().String()
+	if extension, ok := field.(pgs.Extension); ok {
+		message, name = extension.Extendee(), strings.TrimPrefix(extension.FullyQualifiedName(), ".")
+	}
+
+	report := func(rule, reason string) {
+		m.reportUnsupported(unsupportedRule{
+			File:    field.File().Name().String(),
+			Message: strings.TrimPrefix(message.FullyQualifiedName(), "."),
+			Field:   name,
+			Rule:    prefix + rule,
+			Reason:  reason,
+		})
+	}
+
+	if len(rules.GetCel()) > 0 {
+		report("cel", reasonCEL)
+	}
+
+	// Predefined rules are extensions of the rules for each type.
+	reflected := rules.ProtoReflect()
+	if typeField := reflected.WhichOneof(reflected.Descriptor().Oneofs().ByName("type")); typeField != nil {
+		reflected.Get(typeField).Message().Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+			if fd.IsExtension() {
+				report(fmt.Sprintf("%s.(%s)", typeField.Name(), fd.FullName()), reasonPredefined)
+			}
+
+			return true
+		})
+	}
+
+	switch {
+	case rules.GetString() != nil:
+		str := rules.GetString()
+		if str.LenBytes != nil {
+			report("string.len_bytes", reasonByteLength)
+		}
+
+		if str.MinBytes != nil {
+			report("string.min_bytes", reasonByteLength)
+		}
+
+		if str.MaxBytes != nil {
+			report("string.max_bytes", reasonByteLength)
+		}
+
+	case rules.GetBytes() != nil:
+		bytes := rules.GetBytes()
+		if bytes.Suffix != nil {
+			report("bytes.suffix", reasonAnnotated)
+		}
+
+		if bytes.Contains != nil {
+			report("bytes.contains", reasonAnnotated)
+		}
+
+		if bytes.Pattern != nil {
+			report("bytes.pattern", reasonAnnotated)
+		}
+
+		if len(bytes.GetPrefix())%3 != 0 {
+			report("bytes.prefix", "it is only checked as far as it maps onto whole base64 characters")
+		}
+
+	case rules.GetDuration() != nil:
+		// The sign of a duration is checked, but its magnitude isn't.
+		duration := rules.GetDuration()
+		if duration.HasGt() {
+			report("duration.gt", reasonAnnotated)
+		}
+
+		if duration.HasGte() {
+			report("duration.gte", reasonAnnotated)
+		}
+
+		if duration.HasLt() {
+			report("duration.lt", reasonAnnotated)
+		}
+
+		if duration.HasLte() {
+			report("duration.lte", reasonAnnotated)
+		}
+
+	case rules.GetTimestamp() != nil:
+		timestamp := rules.GetTimestamp()
+		if timestamp.GetGtNow() {
+			report("timestamp.gt_now", reasonAnnotated)
+		}
+
+		if timestamp.GetLtNow() {
+			report("timestamp.lt_now", reasonAnnotated)
+		}
+
+		if timestamp.Within != nil {
+			report("timestamp.within", reasonAnnotated)
+		}
+
+	case rules.GetRepeated() != nil:
+		m.checkFieldRules(field, rules.GetRepeated().GetItems(), prefix+"repeated.items.")
+
+	case rules.GetMap() != nil:
+		m.checkFieldRules(field, rules.GetMap().GetKeys(), prefix+"map.keys.")
+		m.checkFieldRules(field, rules.GetMap().GetValues(), prefix+"map.values.")
+	}
+}
+
+// addWarningsReport writes the unsupported rules to the file named by the warnings_report parameter, as a JSON array.
+func (m *Module) addWarningsReport() {
+	if m.warningsReport == "" {
+		return
+	}
+
+	rules := slices.SortedFunc(maps.Keys(m.unsupportedRules), func(a, b unsupportedRule) int {
+		return cmp.Or(
+			strings.Compare(a.File, b.File),
+			strings.Compare(a.Message, b.Message),
+			strings.Compare(a.Field, b.Field),
+			strings.Compare(a.Rule, b.Rule),
+		)
+	})
+
+	if rules == nil {
+		rules = []unsupportedRule{}
+	}
+
+	data, err := json.MarshalIndent(rules, "", "  ")
+	m.CheckErr(err, "failed to marshal warnings report")
+	m.AddGeneratorFile(m.warningsReport, string(data)+"\n")
+}
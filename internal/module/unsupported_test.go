@@ -0,0 +1,57 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarningsReport(t *testing.T) {
+	res := render(t, "warnings_report=warnings.json")
+	require.Empty(t, res.GetError())
+
+	var report []map[string]string
+	for _, file := range res.GetFile() {
+		if file.GetName() == "warnings.json" {
+			require.NoError(t, json.Unmarshal([]byte(file.GetContent()), &report))
+		}
+	}
+
+	rules := make(map[string][]string)
+	for _, entry := range report {
+		require.NotEmpty(t, entry["reason"])
+		key := entry["message"] + "." + entry["field"]
+		rules[key] = append(rules[key], entry["rule"])
+	}
+
+	// Byte lengths of strings are only approximated, since JSON Schema measures strings in characters.
+	require.ElementsMatch(t, []string{"string.min_bytes", "string.max_bytes"}, rules["testproto.StringRulesTest.bytes_field"])
+	require.ElementsMatch(t, []string{"string.len_bytes"}, rules["testproto.StringRulesTest.fixed_bytes_field"])
+	require.Contains(t, rules["testproto.ByteRulesTest.prefix_field"], "bytes.suffix")
+}
+
+func TestStrict(t *testing.T) {
+	output := renderFailure(t, "strict=true,include=testproto.StringRulesTest")
+	require.Contains(t, output, "rule string.min_bytes can't be expressed in JSON Schema")
+	require.Contains(t, output, "set strict=false to generate the schema regardless")
+}
+
+func TestExtensionWarnings(t *testing.T) {
+	res := render(t, "warnings_report=warnings.json")
+
+	var report []map[string]string
+	require.NoError(t, json.Unmarshal([]byte(content(t, res, "warnings.json")), &report))
+
+	// Extensions are reported under the message that they extend.
+	require.Contains(t, report, map[string]string{
+		"file":    "testproto/proto2.proto",
+		"message": "testproto.Proto2Test",
+		"field":   "testproto.extension_rules_field",
+		"rule":    "string.max_bytes",
+		"reason":  "JSON Schema measures strings in characters, so it is only approximated",
+	})
+}
@@ -4,8 +4,11 @@
 package module
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"strings"
 
 	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	pgs "github.com/lyft/protoc-gen-star/v2"
@@ -16,12 +19,40 @@ import (
 	"github.com/cerbos/protoc-gen-jsonschema/internal/jsonschema"
 )
 
+// anyTypeURLPrefix is the prefix of the type URLs that identify the messages packed into an Any.
+const anyTypeURLPrefix = "type.googleapis.com/"
+
+// fieldMaskPattern matches a comma-separated list of field paths, each of which is a dot-separated list of
+// lowerCamelCase field names.
+const fieldMaskPattern = `^(?:[a-z][a-zA-Z0-9]*(?:\.[a-z][a-zA-Z0-9]*)*(?:,[a-z][a-zA-Z0-9]*(?:\.[a-z][a-zA-Z0-9]*)*)*)?$`
+
+// timestampPattern matches the RFC 3339 timestamps that protojson accepts, which have years between 0001 and 9999 and
+// at most nanosecond precision. Timestamps at the very edges of the range may still be rejected depending on their
+// offset.
+const timestampPattern = `^(?:000[1-9]|00[1-9]\d|0[1-9]\d{2}|[1-9]\d{3})-(?:0[1-9]|1[0-2])-(?:0[1-9]|[12]\d|3[01])` +
+	`T(?:[01]\d|2[0-3]):[0-5]\d:[0-5]\d(?:\.\d{1,9})?(?:Z|[+-](?:[01]\d|2[0-3]):[0-5]\d)$`
+
+// maxDurationSeconds is the largest number of seconds in a duration, which is roughly 10,000 years.
+const maxDurationSeconds = 315_576_000_000
+
+// durationPattern matches the canonical protojson encoding of durations, which have at most nanosecond precision and
+// at most maxDurationSeconds whole seconds in either direction.
+var durationPattern = `^-?(?:` + strings.Join(integerRangeAlternatives(new(big.Int), big.NewInt(maxDurationSeconds)), "|") +
+	`)(?:\.\d{1,9})?s$`
+
+const (
+	negativeDuration    = `^-`
+	nonNegativeDuration = `^[^-]`
+	zeroDuration        = `^0(?:\.0+)?s$`
+)
+
 type wellKnownType pgs.WellKnownType
 
 const (
 	wellKnownTypeAny       = wellKnownType(pgs.AnyWKT)
 	wellKnownTypeDuration  = wellKnownType(pgs.DurationWKT)
 	wellKnownTypeEmpty     = wellKnownType(pgs.EmptyWKT)
+	wellKnownTypeFieldMask = wellKnownType("FieldMask")
 	wellKnownTypeListValue = wellKnownType(pgs.ListValueWKT)
 	wellKnownTypeStruct    = wellKnownType(pgs.StructWKT)
 	wellKnownTypeTimestamp = wellKnownType(pgs.TimestampWKT)
@@ -33,82 +64,145 @@ func (t wellKnownType) FullyQualifiedName() string {
 }
 
 func (m *Module) defineAny() jsonschema.Schema {
-	m.Debug("defineAny")
+	m.debug("defineAny")
 	typeURL := jsonschema.NewStringSchema()
-	typeURL.Title = "Type URL"
+	typeURL.Title = m.title("Type URL")
 	typeURL.Description = "A URL/resource name whose content describes the type of the serialized message."
-	typeURL.Format = jsonschema.StringFormatURIReference
 
 	schema := jsonschema.NewObjectSchema()
-	schema.Title = "Any"
+	schema.Title = m.title("Any")
 	schema.Description = "An arbitrary serialized message, along with a URL that describes the type of the serialized message."
-	schema.Properties["@type"] = typeURL
+	schema.Properties = jsonschema.NewProperties()
+	schema.Properties.Set("@type", jsonschema.AllOf(m.applyFormat(typeURL, jsonschema.StringFormatURIReference, []jsonschema.NonTrivialSchema{typeURL})...))
+	schema.Required = []string{"@type"}
 	schema.AdditionalProperties = jsonschema.True
+
+	for _, message := range m.anyMessages {
+		schema.AnyOf = append(schema.AnyOf, m.schemaForAnyMessage(message))
+	}
+
 	return schema
 }
 
+// schemaForAnyMessage matches an Any containing the given message. protojson adds the type URL to the JSON encoding of
+// the message, unless the message is a well-known type with a special encoding, in which case the encoding is nested
+// under a value property.
+func (m *Module) schemaForAnyMessage(message pgs.Message) jsonschema.NonTrivialSchema {
+	m.debug("schemaForAnyMessage")
+	typeURL := jsonschema.NewStringSchema()
+	typeURL.Const = jsonschema.String(anyTypeURLPrefix + strings.TrimPrefix(message.FullyQualifiedName(), "."))
+
+	if message.IsWellKnown() || isFieldMask(message) {
+		schema := jsonschema.NewClosedObjectSchema()
+		schema.Properties.Set("@type", typeURL)
+		schema.Properties.Set("value", m.schemaForEmbed(message, nil))
+		schema.Required = []string{"@type"}
+		return schema
+	}
+
+	schema, constraints := m.schemaForMessageFields(message)
+	schema.Properties.SetFirst("@type", typeURL)
+	schema.Required = append([]string{"@type"}, schema.Required...)
+	m.applyPropertyOrder(schema)
+	return jsonschema.AllOf(append([]jsonschema.NonTrivialSchema{schema}, constraints...)...)
+}
+
 func (m *Module) defineDuration() jsonschema.Schema {
-	m.Debug("defineDuration")
+	m.debug("defineDuration")
 	schema := jsonschema.NewStringSchema()
-	schema.Title = "Duration"
-	schema.Description = "A signed, fixed-length span of time represented as a count of seconds and fractions of seconds at nanosecond resolution."
-	schema.Pattern = `^-?(?:0|[1-9]\d*)(?:\.\d+)?s$`
+	schema.Title = m.title("Duration")
+	schema.Description = "A signed, fixed-length span of time represented as a count of seconds and fractions of seconds at nanosecond resolution, " +
+		"in the range of ±315,576,000,000 seconds."
+	schema.Pattern = durationPattern
 	return schema
 }
 
 func (m *Module) defineEmpty() jsonschema.Schema {
-	m.Debug("defineEmpty")
-	schema := jsonschema.NewObjectSchema()
-	schema.Title = "Empty"
+	m.debug("defineEmpty")
+	schema := jsonschema.NewClosedObjectSchema()
+	schema.Title = m.title("Empty")
 	schema.Description = "A generic empty message."
-	schema.AdditionalProperties = jsonschema.False
+	return schema
+}
+
+func (m *Module) defineFieldMask() jsonschema.Schema {
+	m.debug("defineFieldMask")
+	schema := jsonschema.NewStringSchema()
+	schema.Title = m.title("FieldMask")
+	schema.Description = "A set of symbolic field paths."
+	schema.Pattern = fieldMaskPattern
 	return schema
 }
 
 func (m *Module) defineListValue() jsonschema.Schema {
-	m.Debug("defineListValue")
+	m.debug("defineListValue")
 	schema := jsonschema.NewArraySchema()
-	schema.Title = "ListValue"
+	schema.Title = m.title("ListValue")
 	schema.Description = "A repeated field of dynamically-typed values."
 	schema.Items = m.ref(wellKnownTypeValue, m.defineValue)
 	return schema
 }
 
 func (m *Module) defineStruct() jsonschema.Schema {
-	m.Debug("defineStruct")
+	m.debug("defineStruct")
 	schema := jsonschema.NewObjectSchema()
-	schema.Title = "Struct"
+	schema.Title = m.title("Struct")
 	schema.Description = "A structured data value, consisting of fields which map to dynamically-typed values."
 	schema.AdditionalProperties = m.ref(wellKnownTypeValue, m.defineValue)
 	return schema
 }
 
 func (m *Module) defineTimestamp() jsonschema.Schema {
-	m.Debug("defineTimestamp")
+	m.debug("defineTimestamp")
 	schema := jsonschema.NewStringSchema()
-	schema.Title = "Timestamp"
+	schema.Title = m.title("Timestamp")
 	schema.Description = "A point in time, independent of any time zone or calendar."
-	schema.Format = jsonschema.StringFormatDateTime
-	return schema
+	schema.Pattern = timestampPattern
+	return jsonschema.AllOf(m.applyFormat(schema, jsonschema.StringFormatDateTime, []jsonschema.NonTrivialSchema{schema})...)
 }
 
 func (m *Module) defineValue() jsonschema.Schema {
-	m.Debug("defineValue")
+	m.debug("defineValue")
 	return &jsonschema.GenericSchema{
-		Title:       "Value",
+		Title:       m.title("Value"),
 		Description: "A dynamically-typed value.",
+		AnyOf: []jsonschema.NonTrivialSchema{
+			jsonschema.NewNullSchema(),
+			jsonschema.NewNumberSchema(),
+			jsonschema.NewStringSchema(),
+			jsonschema.NewBooleanSchema(),
+			m.ref(wellKnownTypeStruct, m.defineStruct),
+			m.ref(wellKnownTypeListValue, m.defineListValue),
+		},
+	}
+}
+
+// isFieldMask reports whether the message is google.protobuf.FieldMask, which protojson encodes as a string even
+// though it isn't recognized as a well-known type by protoc-gen-star.
+func isFieldMask(message pgs.Message) bool {
+	return message.FullyQualifiedName() == wellKnownTypeFieldMask.FullyQualifiedName()
+}
+
+// isWrapper reports whether the message is one of the wrapper types, which protojson encodes as the wrapped scalar.
+func isWrapper(message pgs.Message) bool {
+	switch message.WellKnownType() {
+	case pgs.BoolValueWKT, pgs.BytesValueWKT, pgs.DoubleValueWKT, pgs.FloatValueWKT, pgs.Int32ValueWKT,
+		pgs.Int64ValueWKT, pgs.StringValueWKT, pgs.UInt32ValueWKT, pgs.UInt64ValueWKT:
+		return true
+	default:
+		return false
 	}
 }
 
 func (m *Module) schemaForWellKnownType(name pgs.WellKnownType, rules *validate.FieldRules) jsonschema.Schema {
-	m.Debug("schemaForWellKnownType")
+	m.debug("schemaForWellKnownType")
 	switch name {
 	case pgs.AnyWKT:
 		return m.schemaForAny(rules.GetAny())
 	case pgs.BoolValueWKT:
 		return m.schemaForBool(rules.GetBool())
 	case pgs.BytesValueWKT:
-		return m.schemaForBytes()
+		return m.schemaForBytes(rules.GetBytes())
 	case pgs.DoubleValueWKT:
 		return m.schemaForNumericScalar(pgs.DoubleT, rules)
 	case pgs.DurationWKT:
@@ -142,7 +236,7 @@ func (m *Module) schemaForWellKnownType(name pgs.WellKnownType, rules *validate.
 }
 
 func (m *Module) schemaForAny(rules *validate.AnyRules) jsonschema.Schema {
-	m.Debug("schemaForAny")
+	m.debug("schemaForAny")
 	schemas := []jsonschema.NonTrivialSchema{m.ref(wellKnownTypeAny, m.defineAny)}
 
 	if rules != nil {
@@ -159,17 +253,19 @@ func (m *Module) schemaForAny(rules *validate.AnyRules) jsonschema.Schema {
 }
 
 func (m *Module) schemaForAnyIn(typeURLs []string) *jsonschema.ObjectSchema {
-	m.Debug("schemaForAnyIn")
+	m.debug("schemaForAnyIn")
 	typeURL := jsonschema.NewStringSchema()
 	typeURL.Enum = typeURLs
 
 	schema := jsonschema.NewObjectSchema()
-	schema.Properties["@type"] = typeURL
+	schema.Properties = jsonschema.NewProperties()
+	schema.Properties.Set("@type", typeURL)
+	schema.Required = []string{"@type"}
 	return schema
 }
 
 func (m *Module) schemaForDuration(rules *validate.DurationRules) jsonschema.Schema {
-	m.Debug("schemaForDuration")
+	m.debug("schemaForDuration")
 	schemas := []jsonschema.NonTrivialSchema{m.ref(wellKnownTypeDuration, m.defineDuration)}
 
 	if rules != nil {
@@ -182,15 +278,98 @@ func (m *Module) schemaForDuration(rules *validate.DurationRules) jsonschema.Sch
 		}
 
 		if len(rules.NotIn) > 0 {
-			schemas = append(schemas, jsonschema.Not(m.schemaForDurationIn(rules.In)))
+			schemas = append(schemas, jsonschema.Not(m.schemaForDurationIn(rules.NotIn)))
 		}
+
+		schemas = append(schemas, m.schemaForDurationBounds(rules)...)
 	}
 
 	return jsonschema.AllOf(schemas...)
 }
 
+// schemaForDurationBounds records duration comparisons as annotations. When a bound is at zero, the sign of the
+// duration is also enforced with a pattern. protovalidate treats an upper bound below the lower bound as excluding the
+// range between them, so each bound then gets its own alternative.
+func (m *Module) schemaForDurationBounds(rules *validate.DurationRules) []jsonschema.NonTrivialSchema {
+	m.debug("schemaForDurationBounds")
+	lower := cmp.Or(rules.GetGt(), rules.GetGte())
+	upper := cmp.Or(rules.GetLt(), rules.GetLte())
+	if lower != nil && upper != nil && durationLess(upper, lower) {
+		above := m.schemaForDurationBounds(&validate.DurationRules{GreaterThan: rules.GreaterThan})
+		below := m.schemaForDurationBounds(&validate.DurationRules{LessThan: rules.LessThan})
+		return []jsonschema.NonTrivialSchema{jsonschema.AnyOf(jsonschema.AllOf(above...), jsonschema.AllOf(below...))}
+	}
+
+	bounds := jsonschema.NewStringSchema()
+	var schemas []jsonschema.NonTrivialSchema
+	var patterns []string
+
+	switch {
+	case rules.GetGt() != nil:
+		bounds.SetExtension("x-exclusiveMinimum", m.protoJSONString(rules.GetGt()))
+		if !durationIsNegative(rules.GetGt()) {
+			patterns = append(patterns, nonNegativeDuration)
+		}
+
+		if durationIsZero(rules.GetGt()) {
+			zero := jsonschema.NewStringSchema()
+			zero.Pattern = zeroDuration
+			schemas = append(schemas, jsonschema.Not(zero))
+		}
+
+	case rules.GetGte() != nil:
+		bounds.SetExtension("x-minimum", m.protoJSONString(rules.GetGte()))
+		if !durationIsNegative(rules.GetGte()) {
+			patterns = append(patterns, nonNegativeDuration)
+		}
+	}
+
+	switch {
+	case rules.GetLt() != nil:
+		bounds.SetExtension("x-exclusiveMaximum", m.protoJSONString(rules.GetLt()))
+		if durationIsNegative(rules.GetLt()) || durationIsZero(rules.GetLt()) {
+			patterns = append(patterns, negativeDuration)
+		}
+
+	case rules.GetLte() != nil:
+		bounds.SetExtension("x-maximum", m.protoJSONString(rules.GetLte()))
+		if durationIsNegative(rules.GetLte()) {
+			patterns = append(patterns, negativeDuration)
+		}
+	}
+
+	if len(bounds.Extensions) == 0 {
+		return nil
+	}
+
+	for i, pattern := range patterns {
+		if i == 0 {
+			bounds.Pattern = pattern
+			continue
+		}
+
+		match := jsonschema.NewStringSchema()
+		match.Pattern = pattern
+		schemas = append(schemas, match)
+	}
+
+	return append([]jsonschema.NonTrivialSchema{bounds}, schemas...)
+}
+
+func durationIsNegative(d *duration.Duration) bool {
+	return d.GetSeconds() < 0 || d.GetNanos() < 0
+}
+
+func durationIsZero(d *duration.Duration) bool {
+	return d.GetSeconds() == 0 && d.GetNanos() == 0
+}
+
+func durationLess(a, b *duration.Duration) bool {
+	return a.GetSeconds() < b.GetSeconds() || (a.GetSeconds() == b.GetSeconds() && a.GetNanos() < b.GetNanos())
+}
+
 func (m *Module) schemaForDurationIn(durations []*duration.Duration) *jsonschema.StringSchema {
-	m.Debug("schemaForDurationIn")
+	m.debug("schemaForDurationIn")
 	schema := jsonschema.NewStringSchema()
 	for _, duration := range durations {
 		schema.Enum = append(schema.Enum, m.protoJSONString(duration))
@@ -199,27 +378,73 @@ func (m *Module) schemaForDurationIn(durations []*duration.Duration) *jsonschema
 }
 
 func (m *Module) schemaForTimestamp(rules *validate.TimestampRules) jsonschema.Schema {
-	m.Debug("schemaForTimestamp")
+	m.debug("schemaForTimestamp")
 	schemas := []jsonschema.NonTrivialSchema{m.ref(wellKnownTypeTimestamp, m.defineTimestamp)}
 
 	if rules != nil {
 		if rules.Const != nil {
 			schemas = append(schemas, m.schemaForProtoJSONStringConst(rules.Const))
 		}
+
+		if bounds := m.schemaForTimestampBounds(rules); len(bounds.Extensions) > 0 || len(bounds.AnyOf) > 0 {
+			schemas = append(schemas, bounds)
+		}
 	}
 
 	return jsonschema.AllOf(schemas...)
 }
 
+// schemaForTimestampBounds expresses timestamp comparisons using the formatMinimum/formatMaximum family of keywords
+// understood by ajv-formats. Comparisons relative to the current time can't be checked statically, so they're
+// recorded as annotations. protovalidate treats an upper bound before the lower bound as excluding the range between
+// them, so each bound then gets its own alternative.
+func (m *Module) schemaForTimestampBounds(rules *validate.TimestampRules) *jsonschema.StringSchema {
+	m.debug("schemaForTimestampBounds")
+	schema := jsonschema.NewStringSchema()
+	if rules.Within != nil {
+		schema.SetExtension("x-within", m.protoJSONString(rules.GetWithin()))
+	}
+
+	lower := cmp.Or(rules.GetGt(), rules.GetGte())
+	upper := cmp.Or(rules.GetLt(), rules.GetLte())
+	if lower != nil && upper != nil && upper.AsTime().Before(lower.AsTime()) {
+		schema.AnyOf = []jsonschema.NonTrivialSchema{
+			m.schemaForTimestampBounds(&validate.TimestampRules{GreaterThan: rules.GreaterThan}),
+			m.schemaForTimestampBounds(&validate.TimestampRules{LessThan: rules.LessThan}),
+		}
+		return schema
+	}
+
+	switch {
+	case rules.GetGt() != nil:
+		schema.SetExtension("formatExclusiveMinimum", m.protoJSONString(rules.GetGt()))
+	case rules.GetGte() != nil:
+		schema.SetExtension("formatMinimum", m.protoJSONString(rules.GetGte()))
+	case rules.GetGtNow():
+		schema.SetExtension("x-gt-now", true)
+	}
+
+	switch {
+	case rules.GetLt() != nil:
+		schema.SetExtension("formatExclusiveMaximum", m.protoJSONString(rules.GetLt()))
+	case rules.GetLte() != nil:
+		schema.SetExtension("formatMaximum", m.protoJSONString(rules.GetLte()))
+	case rules.GetLtNow():
+		schema.SetExtension("x-lt-now", true)
+	}
+
+	return schema
+}
+
 func (m *Module) schemaForProtoJSONStringConst(value proto.Message) *jsonschema.StringSchema {
-	m.Debug("schemaForProtoJSONStringConst")
+	m.debug("schemaForProtoJSONStringConst")
 	schema := jsonschema.NewStringSchema()
 	schema.Const = jsonschema.String(m.protoJSONString(value))
 	return schema
 }
 
 func (m *Module) protoJSONString(value proto.Message) string {
-	m.Debug("protoJSONString")
+	m.debug("protoJSONString")
 	data, err := protojson.Marshal(value)
 	m.CheckErr(err, "failed to marshal value to proto JSON")
 
@@ -0,0 +1,76 @@
+// Copyright 2021-2025 Zenauth Ltd.
+// SPDX-License-Identifier: Apache-2.0
+
+package module_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnyRequiresType(t *testing.T) {
+	doc := document(t, render(t, ""), "testproto/AnyRulesTest.schema.json")
+	anySchema := lookup(t, doc, "definitions", "google.protobuf.Any")
+
+	require.Equal(t, []any{"@type"}, lookup(t, anySchema, "required"))
+	require.Equal(t, true, lookup(t, anySchema, "additionalProperties"))
+	require.NotContains(t, anySchema, "anyOf")
+}
+
+func TestExpandAny(t *testing.T) {
+	doc := document(t, render(t, "expand_any=true"), "testproto/AnyRulesTest.schema.json")
+
+	// Each message in the request is an alternative, identified by its type URL.
+	alternatives := make(map[string]any)
+	for _, alternative := range lookup(t, doc, "definitions", "google.protobuf.Any", "anyOf").([]any) {
+		object := alternative
+		if allOf, ok := alternative.(map[string]any)["allOf"]; ok {
+			object = allOf.([]any)[0]
+		}
+
+		alternatives[lookup(t, object, "properties", "@type", "const").(string)] = alternative
+	}
+
+	require.Equal(t, map[string]any{
+		"type":                 "object",
+		"required":             []any{"@type"},
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"@type": map[string]any{"type": "string", "const": "type.googleapis.com/testproto.GenerateOptionTest"},
+			"state": map[string]any{"$ref": "#/definitions/testproto.DummyEnum"},
+		},
+	}, alternatives["type.googleapis.com/testproto.GenerateOptionTest"])
+
+	// Well-known types with a special JSON encoding are wrapped in a value property.
+	require.Equal(t, map[string]any{"$ref": "#/definitions/google.protobuf.Duration"}, lookup(t, alternatives["type.googleapis.com/google.protobuf.Duration"], "properties", "value"))
+}
+
+func TestReversedDurationBounds(t *testing.T) {
+	properties := lookup(t, document(t, render(t, ""), "testproto/DurationRulesTest.schema.json"), "properties")
+
+	// An upper bound below the lower bound excludes the range between them, so each bound is an alternative, and the
+	// sign of the duration is only enforced by the bound that implies it.
+	require.Equal(t, []any{
+		map[string]any{"type": "string", "pattern": "^[^-]", "x-exclusiveMinimum": "10s"},
+		map[string]any{"type": "string", "x-exclusiveMaximum": "5s"},
+	}, lookup(t, properties, "reversedField", "allOf", "1", "anyOf"))
+
+	require.Equal(t, []any{
+		map[string]any{"type": "string", "pattern": "^[^-]", "x-exclusiveMinimum": "1s"},
+		map[string]any{"type": "string", "pattern": "^-", "x-exclusiveMaximum": "-1s"},
+	}, lookup(t, properties, "reversedSignField", "allOf", "1", "anyOf"))
+}
+
+func TestReversedTimestampBounds(t *testing.T) {
+	doc := document(t, render(t, ""), "testproto/TimestampRulesTest.schema.json")
+
+	// An upper bound before the lower bound excludes the range between them, so each bound is an alternative.
+	require.Equal(t, map[string]any{
+		"type": "string",
+		"anyOf": []any{
+			map[string]any{"type": "string", "formatExclusiveMinimum": "1970-01-01T00:16:40Z"},
+			map[string]any{"type": "string", "formatExclusiveMaximum": "1970-01-01T00:08:20Z"},
+		},
+	}, lookup(t, doc, "properties", "reversedField", "allOf", "1"))
+}